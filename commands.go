@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/flapjacck/CoCDB/internal/config"
+	"github.com/flapjacck/CoCDB/internal/data"
+)
+
+// runCommand dispatches a command-line subcommand and returns the exit code.
+func runCommand(cfg *config.Config, args []string) int {
+	switch args[0] {
+	case "validate":
+		return runValidate(cfg, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\nusage: cocdb [validate [--allow-missing-kinds]]\n", args[0])
+		return 2
+	}
+}
+
+// runValidate checks every data file against its category template and
+// prints one line per violation. It exits non-zero if anything is wrong.
+//
+// References to kinds with no data files are violations too, unless
+// --allow-missing-kinds is given; then they are printed as warnings.
+func runValidate(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	allowMissing := flags.Bool("allow-missing-kinds", false, "pass references to kinds with no data files, with a warning")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	fsys, source := dataFS(cfg)
+	loader := data.NewLoader(fsys)
+	loader.AllowMissingKinds = *allowMissing
+	violations, err := loader.Validate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+
+	for _, v := range violations {
+		fmt.Println(v)
+	}
+	if len(violations) > 0 {
+		fmt.Fprintf(os.Stderr, "%d violation(s) found in %s\n", len(violations), source)
+		return 1
+	}
+
+	// References to kinds without data files passed, but the gap should show.
+	if *allowMissing {
+		warnings, err := loader.Unchecked()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 1
+		}
+		for _, w := range warnings {
+			fmt.Fprintln(os.Stderr, "warning:", w)
+		}
+	}
+
+	fmt.Printf("all data files in %s are valid\n", source)
+	return 0
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+
+	"github.com/flapjacck/CoCDB/internal/config"
+)
+
+// The data directory and static assets are compiled into the binary, so a
+// deployment needs nothing but the executable.
+var (
+	//go:embed data
+	embeddedData embed.FS
+
+	//go:embed static
+	embeddedStatic embed.FS
+)
+
+// dataFS returns the data tree to serve together with a description of where
+// it comes from: the on-disk DATA_DIR when set, else the embedded snapshot.
+func dataFS(cfg *config.Config) (fs.FS, string) {
+	if cfg.DataDir != "" {
+		return os.DirFS(cfg.DataDir), cfg.DataDir
+	}
+	sub, _ := fs.Sub(embeddedData, "data")
+	return sub, "embedded data"
+}
+
+// staticFS returns the embedded static assets.
+func staticFS() fs.FS {
+	sub, _ := fs.Sub(embeddedStatic, "static")
+	return sub
+}
@@ -1,91 +1,250 @@
-// Package cache provides a thread-safe in-memory key-value cache with TTL support.
+// Package cache provides a thread-safe, size-bounded in-memory cache with TTL
+// support and least-recently-used eviction.
 // Expired entries are automatically evicted by a background goroutine.
 package cache
 
 import (
+	"container/list"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
 
+// minEvictInterval bounds how often evictLoop scans for expired entries,
+// so very short (or zero) TTLs do not make it spin.
+const minEvictInterval = time.Second
+
 // entry represents a single cached item with an expiration timestamp.
-type entry struct {
-	value     interface{}
+type entry[V any] struct {
+	key       string
+	value     V
 	expiresAt time.Time
 }
 
-// Cache is a concurrent-safe in-memory cache with automatic TTL-based expiration.
-type Cache struct {
-	mu      sync.RWMutex
-	entries map[string]entry
-	ttl     time.Duration
-	stop    chan struct{}
+// call is an in-flight load shared by concurrent GetOrLoad callers.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Stats are counters describing how a cache has been used.
+type Stats struct {
+	Entries    int     `json:"entries"`
+	MaxEntries int     `json:"maxEntries"`
+	Hits       uint64  `json:"hits"`
+	Misses     uint64  `json:"misses"`
+	Evictions  uint64  `json:"evictions"` // removed to make room
+	Expired    uint64  `json:"expired"`   // removed after their TTL
+	HitRate    float64 `json:"hitRate"`
 }
 
-// New creates a Cache with the given TTL and starts a background eviction loop.
-func New(ttl time.Duration) *Cache {
-	c := &Cache{
-		entries: make(map[string]entry),
-		ttl:     ttl,
-		stop:    make(chan struct{}),
+// Cache is a concurrent-safe in-memory cache of values of type V. Entries
+// expire after a TTL, and once the cache holds maxEntries values, adding
+// another evicts the least recently used one.
+type Cache[V any] struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element // values are *entry[V]
+	lru        *list.List               // most recently used at the front
+	inflight   map[string]*call[V]
+	generation uint64 // bumped by deletions, so loads started before one are not cached
+	ttl        time.Duration
+	maxEntries int
+	stats      Stats
+	stop       chan struct{}
+}
+
+// New creates a Cache with the given default TTL and capacity, and starts a
+// background eviction loop. A maxEntries of 0 or less means unbounded.
+func New[V any](ttl time.Duration, maxEntries int) *Cache[V] {
+	c := &Cache[V]{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		inflight:   make(map[string]*call[V]),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		stop:       make(chan struct{}),
 	}
 	go c.evictLoop()
 	return c
 }
 
 // Get retrieves a value by key. Returns (value, true) if found and not expired,
-// or (nil, false) otherwise.
-func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	e, exists := c.entries[key]
-	if !exists || time.Now().After(e.expiresAt) {
-		return nil, false
+// or the zero value and false otherwise.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(key)
+}
+
+// get looks up key, counting the hit or miss. c.mu must be held.
+func (c *Cache[V]) get(key string) (V, bool) {
+	el, exists := c.entries[key]
+	if exists {
+		e := el.Value.(*entry[V])
+		if time.Now().Before(e.expiresAt) {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			return e.value, true
+		}
+		c.remove(el)
+		c.stats.Expired++
 	}
-	return e.value, true
+	c.stats.Misses++
+	var zero V
+	return zero, false
+}
+
+// Set stores a value in the cache with the default TTL.
+func (c *Cache[V]) Set(key string, value V) {
+	c.SetWithTTL(key, value, c.ttl)
 }
 
-// Set stores a value in the cache with the configured TTL.
-func (c *Cache) Set(key string, value interface{}) {
+// SetWithTTL stores a value that expires after ttl instead of the default.
+func (c *Cache[V]) SetWithTTL(key string, value V, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.set(key, value, ttl)
+}
+
+// set stores a value, evicting the least recently used entries beyond
+// maxEntries. c.mu must be held.
+func (c *Cache[V]) set(key string, value V, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl)
+	if el, exists := c.entries[key]; exists {
+		e := el.Value.(*entry[V])
+		e.value, e.expiresAt = value, expiresAt
+		c.lru.MoveToFront(el)
+		return
+	}
 
-	c.entries[key] = entry{
-		value:     value,
-		expiresAt: time.Now().Add(c.ttl),
+	c.entries[key] = c.lru.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// GetOrLoad returns the cached value for key, or calls load to produce it
+// and caches the result with the default TTL. Concurrent callers missing the
+// same key share a single call to load. Errors are returned, not cached.
+// cached reports whether the value was already in the cache.
+func (c *Cache[V]) GetOrLoad(key string, load func() (V, error)) (value V, cached bool, err error) {
+	return c.GetOrLoadWithTTL(key, c.ttl, load)
+}
+
+// GetOrLoadWithTTL is like GetOrLoad, but caches a loaded value for ttl
+// instead of the default. If load panics, the panic is propagated to the
+// caller that ran it and the callers waiting on it get an error.
+func (c *Cache[V]) GetOrLoadWithTTL(key string, ttl time.Duration, load func() (V, error)) (value V, cached bool, err error) {
+	c.mu.Lock()
+	if v, ok := c.get(key); ok {
+		c.mu.Unlock()
+		return v, true, nil
+	}
+	if cl, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-cl.done
+		return cl.value, false, cl.err
 	}
+	cl := &call[V]{done: make(chan struct{})}
+	c.inflight[key] = cl
+	generation := c.generation
+	c.mu.Unlock()
+
+	panicked := true
+	defer func() {
+		if panicked {
+			cl.err = fmt.Errorf("cache: load of %q panicked", key)
+		}
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if cl.err == nil && generation == c.generation {
+			c.set(key, cl.value, ttl)
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+
+	cl.value, cl.err = load()
+	panicked = false
+	return cl.value, false, cl.err
 }
 
 // Delete removes a single entry by key.
-func (c *Cache) Delete(key string) {
+func (c *Cache[V]) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.entries, key)
+	c.generation++
+	if el, exists := c.entries[key]; exists {
+		c.remove(el)
+	}
+}
+
+// DeletePrefix removes every entry whose key starts with prefix and returns
+// how many were removed.
+func (c *Cache[V]) DeletePrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+
+	n := 0
+	for key, el := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+			n++
+		}
+	}
+	return n
 }
 
 // Flush removes all entries from the cache.
-func (c *Cache) Flush() {
+func (c *Cache[V]) Flush() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.entries = make(map[string]entry)
+	c.generation++
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
 }
 
 // Size returns the current number of entries in the cache.
-func (c *Cache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Cache[V]) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return len(c.entries)
 }
 
+// Stats returns a snapshot of the cache's counters.
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Entries = len(c.entries)
+	s.MaxEntries = c.maxEntries
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	return s
+}
+
 // Close stops the background eviction goroutine.
-func (c *Cache) Close() {
+func (c *Cache[V]) Close() {
 	close(c.stop)
 }
 
+// remove unlinks an entry. c.mu must be held.
+func (c *Cache[V]) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*entry[V]).key)
+}
+
 // evictLoop periodically scans and removes expired entries.
-// It runs at half the TTL interval for timely cleanup.
-func (c *Cache) evictLoop() {
-	ticker := time.NewTicker(c.ttl / 2)
+// It runs at half the TTL interval for timely cleanup, but no more often
+// than minEvictInterval.
+func (c *Cache[V]) evictLoop() {
+	ticker := time.NewTicker(max(c.ttl/2, minEvictInterval))
 	defer ticker.Stop()
 
 	for {
@@ -93,9 +252,10 @@ func (c *Cache) evictLoop() {
 		case <-ticker.C:
 			c.mu.Lock()
 			now := time.Now()
-			for key, e := range c.entries {
-				if now.After(e.expiresAt) {
-					delete(c.entries, key)
+			for _, el := range c.entries {
+				if now.After(el.Value.(*entry[V]).expiresAt) {
+					c.remove(el)
+					c.stats.Expired++
 				}
 			}
 			c.mu.Unlock()
@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[int](time.Minute, 2)
+	defer c.Close()
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // b is now the least recently used
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+
+	s := c.Stats()
+	if s.Entries != 2 || s.Evictions != 1 || s.Expired != 0 {
+		t.Errorf("stats = %+v, want 2 entries, 1 eviction, 0 expired", s)
+	}
+	if s.Hits != 3 || s.Misses != 1 {
+		t.Errorf("hits, misses = %d, %d, want 3, 1", s.Hits, s.Misses)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := New[int](time.Minute, 0)
+	defer c.Close()
+
+	c.SetWithTTL("short", 1, time.Millisecond)
+	c.Set("long", 2)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("short"); ok {
+		t.Error("expired entry was returned")
+	}
+	if _, ok := c.Get("long"); !ok {
+		t.Error("unexpired entry was not returned")
+	}
+	if s := c.Stats(); s.Expired != 1 || s.Evictions != 0 || s.Entries != 1 {
+		t.Errorf("stats = %+v, want 1 expired, 0 evictions, 1 entry", s)
+	}
+}
+
+func TestZeroTTL(t *testing.T) {
+	c := New[int](0, 0) // must not panic starting the eviction loop
+	defer c.Close()
+
+	calls := 0
+	load := func() (int, error) { calls++; return 1, nil }
+	c.GetOrLoad("a", load)
+	c.GetOrLoad("a", load)
+	if calls != 2 {
+		t.Errorf("load called %d times, want 2 (nothing is cached with a zero TTL)", calls)
+	}
+}
+
+func TestGetOrLoadSharesConcurrentLoads(t *testing.T) {
+	c := New[int](time.Minute, 0)
+	defer c.Close()
+
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	load := func() (int, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return 42, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	values := make([]int, n)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		values[0], _, _ = c.GetOrLoad("k", load)
+	}()
+	<-started
+	for i := 1; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], _, _ = c.GetOrLoad("k", load)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond) // let the other callers join the load
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("load called %d times, want 1", got)
+	}
+	for i, v := range values {
+		if v != 42 {
+			t.Errorf("caller %d got %d, want 42", i, v)
+		}
+	}
+	if v, cached, err := c.GetOrLoad("k", load); v != 42 || !cached || err != nil {
+		t.Errorf("GetOrLoad after load = %d, %v, %v, want 42, true, nil", v, cached, err)
+	}
+}
+
+func TestGetOrLoadErrorsAreNotCached(t *testing.T) {
+	c := New[int](time.Minute, 0)
+	defer c.Close()
+
+	errLoad := errors.New("load failed")
+	if _, _, err := c.GetOrLoad("k", func() (int, error) { return 0, errLoad }); err != errLoad {
+		t.Fatalf("GetOrLoad error = %v, want %v", err, errLoad)
+	}
+	if v, cached, err := c.GetOrLoad("k", func() (int, error) { return 1, nil }); v != 1 || cached || err != nil {
+		t.Errorf("GetOrLoad = %d, %v, %v, want 1, false, nil", v, cached, err)
+	}
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	c := New[int](time.Minute, 0)
+	defer c.Close()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic in load was not propagated")
+			}
+		}()
+		c.GetOrLoad("k", func() (int, error) { panic("boom") })
+	}()
+
+	// The failed load must not be left in flight or cached.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if v, cached, err := c.GetOrLoad("k", func() (int, error) { return 1, nil }); v != 1 || cached || err != nil {
+			t.Errorf("GetOrLoad after panic = %d, %v, %v, want 1, false, nil", v, cached, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("GetOrLoad blocked on the panicked load")
+	}
+}
+
+func TestGetOrLoadWithTTL(t *testing.T) {
+	c := New[int](time.Minute, 0)
+	defer c.Close()
+
+	c.GetOrLoadWithTTL("k", time.Millisecond, func() (int, error) { return 1, nil })
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("k"); ok {
+		t.Error("value loaded with a short TTL did not expire")
+	}
+}
@@ -5,6 +5,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -20,10 +21,15 @@ type Config struct {
 	// Application settings
 	Environment string
 	LogLevel    string
-	DataDir     string
+	DataDir     string // empty means the data embedded in the binary
+
+	// ReloadInterval is how often DATA_DIR is polled for changed files;
+	// zero disables hot reload.
+	ReloadInterval time.Duration
 
 	// Cache settings
-	CacheTTL time.Duration
+	CacheTTL        time.Duration
+	CacheMaxEntries int // zero or less means unbounded
 
 	// CORS settings
 	CORSOrigins []string
@@ -38,20 +44,27 @@ type Config struct {
 //   - IDLE_TIMEOUT: HTTP idle timeout (default: "120s")
 //   - ENVIRONMENT: Running environment (default: "development")
 //   - LOG_LEVEL: Logging level — debug, info, warn, error (default: "info")
-//   - DATA_DIR: Path to data directory (default: "data")
+//   - DATA_DIR: Path to an on-disk data directory to serve instead of the
+//     embedded snapshot (default: "", the embedded data)
+//   - RELOAD_INTERVAL: How often to poll DATA_DIR for changes, "0" to
+//     disable (default: "2s"; the embedded data never changes)
 //   - CACHE_TTL: Cache time-to-live duration (default: "5m")
+//   - CACHE_MAX_ENTRIES: Entries kept before the least recently used is
+//     evicted, "0" for no limit (default: "1000")
 //   - CORS_ORIGINS: Comma-separated allowed origins (default: "*")
 func Load() *Config {
 	return &Config{
-		Port:         getEnv("PORT", "3000"),
-		ReadTimeout:  getDuration("READ_TIMEOUT", 10*time.Second),
-		WriteTimeout: getDuration("WRITE_TIMEOUT", 10*time.Second),
-		IdleTimeout:  getDuration("IDLE_TIMEOUT", 120*time.Second),
-		Environment:  getEnv("ENVIRONMENT", "development"),
-		LogLevel:     getEnv("LOG_LEVEL", "info"),
-		DataDir:      getEnv("DATA_DIR", "data"),
-		CacheTTL:     getDuration("CACHE_TTL", 5*time.Minute),
-		CORSOrigins:  strings.Split(getEnv("CORS_ORIGINS", "*"), ","),
+		Port:            getEnv("PORT", "3000"),
+		ReadTimeout:     getDuration("READ_TIMEOUT", 10*time.Second),
+		WriteTimeout:    getDuration("WRITE_TIMEOUT", 10*time.Second),
+		IdleTimeout:     getDuration("IDLE_TIMEOUT", 120*time.Second),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		LogLevel:        getEnv("LOG_LEVEL", "info"),
+		DataDir:         getEnv("DATA_DIR", ""),
+		ReloadInterval:  getDuration("RELOAD_INTERVAL", 2*time.Second),
+		CacheTTL:        getDuration("CACHE_TTL", 5*time.Minute),
+		CacheMaxEntries: getInt("CACHE_MAX_ENTRIES", 1000),
+		CORSOrigins:     strings.Split(getEnv("CORS_ORIGINS", "*"), ","),
 	}
 }
 
@@ -73,6 +86,16 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getInt retrieves an integer from an environment variable.
+func getInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
 // getDuration retrieves a duration from an environment variable.
 // The value must be a valid Go duration string (e.g., "10s", "5m").
 func getDuration(key string, fallback time.Duration) time.Duration {
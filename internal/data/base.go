@@ -0,0 +1,15 @@
+package data
+
+// Bases lists the game bases served by the API, in display order. Each has a
+// directory of the same name under the data directory.
+var Bases = []string{"home_village", "builder_base", "clan_capital"}
+
+// IsBase reports whether name is a known base.
+func IsBase(name string) bool {
+	for _, b := range Bases {
+		if b == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Building is the typed representation of a single building data file
+// (e.g. data/home_village/buildings/defensive/x_bow.json).
+type Building struct {
+	Name            string           `json:"name"`
+	Type            string           `json:"type"`
+	Size            Size             `json:"size"`
+	Description     string           `json:"description"`
+	Availability    Availability     `json:"availability"`
+	Attack          *Attack          `json:"attack,omitempty"`
+	Modes           []Mode           `json:"modes,omitempty"`
+	Levels          []Level          `json:"levels,omitempty"`
+	Supercharges    []Supercharge    `json:"supercharges,omitempty"`
+	SpecialUpgrades []SpecialUpgrade `json:"specialUpgrades,omitempty"`
+	Notes           string           `json:"notes,omitempty"`
+	Source          string           `json:"source"`
+	SourceURL       string           `json:"source_url"`
+	SourceLicense   string           `json:"source_license,omitempty"`
+
+	// Unlocks links the troops, spells, heroes and other units unlocked by
+	// the building's levels. It is resolved when the building is served,
+	// not read from the building file.
+	Unlocks []UnitLink `json:"unlocks,omitempty"`
+
+	// Extra holds building-specific top-level fields such as
+	// "lootablePercent", "production" or "triggerRadius".
+	Extra Extra `json:"-"`
+}
+
+// Size is the footprint of a building in tiles.
+type Size struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Availability describes how many copies of a building can be placed.
+// Home Village buildings are keyed by Town Hall level, Builder Base
+// buildings by Builder Hall level and Clan Capital buildings by district
+// and that district's hall level.
+type Availability struct {
+	TownHallLevels    []TownHallAvailability    `json:"townHallLevels,omitempty"`
+	BuilderHallLevels []BuilderHallAvailability `json:"builderHallLevels,omitempty"`
+	Districts         []DistrictAvailability    `json:"districts,omitempty"`
+}
+
+// TownHallAvailability is the number of copies available at one Town Hall level.
+type TownHallAvailability struct {
+	TownHall        int    `json:"townHall"`
+	NumberAvailable int    `json:"numberAvailable"`
+	Notes           string `json:"notes,omitempty"`
+}
+
+// BuilderHallAvailability is the number of copies available at one Builder
+// Hall level.
+type BuilderHallAvailability struct {
+	BuilderHall     int    `json:"builderHall"`
+	NumberAvailable int    `json:"numberAvailable"`
+	Notes           string `json:"notes,omitempty"`
+}
+
+// DistrictAvailability is the number of copies of a Clan Capital building
+// available in one district, per level of the district's hall.
+type DistrictAvailability struct {
+	District   string             `json:"district"`
+	HallLevels []HallAvailability `json:"hallLevels"`
+}
+
+// HallAvailability is the number of copies available at one district hall
+// level (the Capital Hall in Capital Peak, a District Hall elsewhere).
+type HallAvailability struct {
+	HallLevel       int    `json:"hallLevel"`
+	NumberAvailable int    `json:"numberAvailable"`
+	Notes           string `json:"notes,omitempty"`
+}
+
+// Attack describes how a defensive building or trap deals damage.
+type Attack struct {
+	Range          *Range   `json:"range,omitempty"`
+	AttackSpeed    *float64 `json:"attackSpeed,omitempty"`
+	DamageType     string   `json:"damageType,omitempty"`
+	SplashRadius   *float64 `json:"splashRadius,omitempty"`
+	FavoriteTarget string   `json:"favoriteTarget,omitempty"`
+	TargetTypes    []string `json:"targetTypes,omitempty"`
+	Notes          string   `json:"notes,omitempty"`
+
+	// Extra holds attack fields specific to a single building,
+	// e.g. "shotsPerBurst" or "activationRange".
+	Extra Extra `json:"-"`
+}
+
+// Range is an attack range in tiles. Most buildings have a single maximum
+// range; some (e.g. Mortar) also have a blind spot expressed as Min.
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Level holds the stats and upgrade cost for one building level.
+type Level struct {
+	Level               int       `json:"level"`
+	Hitpoints           int       `json:"hitpoints,omitempty"`
+	Cost                Cost      `json:"cost"`
+	BoostCost           *Cost     `json:"boostCost,omitempty"`
+	BuildTime           *Duration `json:"buildTime,omitempty"`
+	TimeToFill          *Duration `json:"timeToFill,omitempty"`
+	CatchUpPoint        *Duration `json:"catchUpPoint,omitempty"`
+	ExperienceGained    int       `json:"experienceGained"`
+	TownHallRequired    int       `json:"townHallRequired,omitempty"`
+	BuilderHallRequired int       `json:"builderHallRequired,omitempty"`
+	Notes               string    `json:"notes,omitempty"`
+
+	// Extra holds level stats specific to a building, such as
+	// "damagePerSecond", "capacity" or "unlockedUnit".
+	Extra Extra `json:"-"`
+}
+
+// Supercharge is one charge level available after a building is maxed.
+type Supercharge struct {
+	ChargeLevel      int       `json:"chargeLevel"`
+	Hitpoints        int       `json:"hitpoints,omitempty"`
+	Cost             Cost      `json:"cost"`
+	BoostCost        *Cost     `json:"boostCost,omitempty"`
+	BuildTime        *Duration `json:"buildTime,omitempty"`
+	TimeToFill       *Duration `json:"timeToFill,omitempty"`
+	CatchUpPoint     *Duration `json:"catchUpPoint,omitempty"`
+	ExperienceGained int       `json:"experienceGained"`
+	TownHallRequired int       `json:"townHallRequired"`
+	Notes            string    `json:"notes,omitempty"`
+
+	// Extra holds charge-level stats specific to a building.
+	Extra Extra `json:"-"`
+}
+
+// SpecialUpgrade is a one-off upgrade such as Gear Up.
+type SpecialUpgrade struct {
+	Name                     string    `json:"name"`
+	Cost                     Cost      `json:"cost"`
+	BuildTime                *Duration `json:"buildTime,omitempty"`
+	RequiredLevel            int       `json:"requiredLevel,omitempty"`
+	CanBeUpgradedAtLevel     int       `json:"canBeUpgradedAtLevel,omitempty"`
+	RequiredBuilderBaseLevel int       `json:"requiredBuilderBaseLevel,omitempty"`
+	Effect                   string    `json:"effect,omitempty"`
+}
+
+// Mode is an alternative attack mode (e.g. Inferno Tower single/multi target).
+// Modes may carry their own level table.
+type Mode struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Levels      []Level `json:"levels,omitempty"`
+
+	// Extra holds mode-specific attack fields such as "range" or "attackSpeed".
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a building, keeping unmodeled fields in Extra.
+func (b *Building) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, b, &b.Extra)
+}
+
+// MarshalJSON encodes a building, including any fields kept in Extra.
+func (b Building) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(b, b.Extra)
+}
+
+// UnmarshalJSON decodes an attack block, keeping unmodeled fields in Extra.
+func (a *Attack) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, a, &a.Extra)
+}
+
+// MarshalJSON encodes an attack block, including any fields kept in Extra.
+func (a Attack) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(a, a.Extra)
+}
+
+// UnmarshalJSON decodes a level, keeping unmodeled stats in Extra.
+func (l *Level) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a level, including any stats kept in Extra.
+func (l Level) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
+
+// UnmarshalJSON decodes a supercharge, keeping unmodeled stats in Extra.
+func (s *Supercharge) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, s, &s.Extra)
+}
+
+// MarshalJSON encodes a supercharge, including any stats kept in Extra.
+func (s Supercharge) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(s, s.Extra)
+}
+
+// UnmarshalJSON decodes a mode, keeping unmodeled fields in Extra.
+func (m *Mode) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, m, &m.Extra)
+}
+
+// MarshalJSON encodes a mode, including any fields kept in Extra.
+func (m Mode) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(m, m.Extra)
+}
+
+// UnmarshalJSON accepts either a single number (maximum range) or an
+// object with "min" and "max".
+func (r *Range) UnmarshalJSON(raw []byte) error {
+	var max float64
+	if err := json.Unmarshal(raw, &max); err == nil {
+		*r = Range{Max: max}
+		return nil
+	}
+
+	var obj struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return fmt.Errorf("range must be a number or {min, max} object")
+	}
+	*r = Range{Min: obj.Min, Max: obj.Max}
+	return nil
+}
+
+// MarshalJSON writes a plain number when there is no minimum range, matching
+// the shape used in the data files.
+func (r Range) MarshalJSON() ([]byte, error) {
+	if r.Min == 0 {
+		return json.Marshal(r.Max)
+	}
+	return json.Marshal(struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}{r.Min, r.Max})
+}
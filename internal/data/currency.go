@@ -0,0 +1,109 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Currency is a canonical resource name used in costs.
+type Currency string
+
+// Known currencies.
+const (
+	Gold       Currency = "gold"
+	Elixir     Currency = "elixir"
+	DarkElixir Currency = "dark_elixir"
+	Gems       Currency = "gems"
+	ShinyOre   Currency = "shiny_ore"
+	GlowyOre   Currency = "glowy_ore"
+	StarryOre  Currency = "starry_ore"
+
+	// Builder Base resources are separate from their Home Village namesakes.
+	BuilderGold   Currency = "builder_gold"
+	BuilderElixir Currency = "builder_elixir"
+
+	// Clan Capital resources.
+	CapitalGold Currency = "capital_gold"
+	RaidMedals  Currency = "raid_medals"
+)
+
+// Currencies lists every known currency in display order.
+var Currencies = []Currency{Gold, Elixir, DarkElixir, Gems, ShinyOre, GlowyOre, StarryOre, BuilderGold, BuilderElixir, CapitalGold, RaidMedals}
+
+// currencyAliases maps normalized spellings found in data files to currencies.
+var currencyAliases = map[string]Currency{
+	"gold":        Gold,
+	"elixir":      Elixir,
+	"dark_elixir": DarkElixir,
+	"darkelixir":  DarkElixir,
+	"de":          DarkElixir,
+	"gems":        Gems,
+	"gem":         Gems,
+	"shiny_ore":   ShinyOre,
+	"glowy_ore":   GlowyOre,
+	"starry_ore":  StarryOre,
+
+	"builder_gold":   BuilderGold,
+	"builder_elixir": BuilderElixir,
+
+	"capital_gold": CapitalGold,
+	"raid_medals":  RaidMedals,
+	"raid_medal":   RaidMedals,
+}
+
+// ParseCurrency maps a currency name as written in a data file (e.g. "Gold",
+// "dark elixir", "Dark-Elixir") to its canonical Currency.
+func ParseCurrency(s string) (Currency, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+	if c, ok := currencyAliases[key]; ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown currency %q", s)
+}
+
+// UnmarshalJSON normalizes the currency spelling, rejecting unknown names.
+func (c *Currency) UnmarshalJSON(raw []byte) error {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("currency must be a string")
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// UnmarshalText normalizes the currency spelling like UnmarshalJSON. It lets
+// currencies key JSON objects, as in per-currency amounts.
+func (c *Currency) UnmarshalText(text []byte) error {
+	parsed, err := ParseCurrency(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// Price is an amount in a single currency.
+type Price struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// Cost is the price of an upgrade. Some upgrades can be paid in more than
+// one currency (e.g. Walls accept Gold or Elixir from level 9); Alternatives
+// lists the other prices, any one of which pays for the upgrade instead.
+type Cost struct {
+	Amount       int64    `json:"amount"`
+	Currency     Currency `json:"currency"`
+	Alternatives []Price  `json:"alternatives,omitempty"`
+}
+
+// Primary returns the main price of the cost.
+func (c Cost) Primary() Price {
+	return Price{Amount: c.Amount, Currency: c.Currency}
+}
+
+// Options returns every accepted price, the primary one first.
+func (c Cost) Options() []Price {
+	return append([]Price{c.Primary()}, c.Alternatives...)
+}
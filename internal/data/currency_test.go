@@ -0,0 +1,81 @@
+package data
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Currency
+		wantErr bool
+	}{
+		{in: "gold", want: Gold},
+		{in: "Gold", want: Gold},
+		{in: " Elixir ", want: Elixir},
+		{in: "dark elixir", want: DarkElixir},
+		{in: "Dark-Elixir", want: DarkElixir},
+		{in: "DE", want: DarkElixir},
+		{in: "gem", want: Gems},
+		{in: "Builder Gold", want: BuilderGold},
+		{in: "Raid Medal", want: RaidMedals},
+		{in: "", wantErr: true},
+		{in: "silver", wantErr: true},
+		{in: "goldd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCurrency(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseCurrency(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseCurrency(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestCostJSON(t *testing.T) {
+	var c Cost
+	raw := `{"amount": 300000, "currency": "Gold", "alternatives": [{"amount": 300000, "currency": "Elixir"}]}`
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	want := []Price{{300000, Gold}, {300000, Elixir}}
+	if got := c.Options(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Options() = %v, want %v", got, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount": 1, "currency": "silver"}`), &c); err == nil {
+		t.Error("Unmarshal accepted an unknown currency")
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 1, "currency": 5}`), &c); err == nil {
+		t.Error("Unmarshal accepted a non-string currency")
+	}
+}
+
+func TestCurrencyMapKeys(t *testing.T) {
+	var storage map[Currency]int64
+	if err := json.Unmarshal([]byte(`{"Gold": 1000, "Dark Elixir": 20}`), &storage); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	want := map[Currency]int64{Gold: 1000, DarkElixir: 20}
+	if !reflect.DeepEqual(storage, want) {
+		t.Errorf("storage = %v, want %v", storage, want)
+	}
+
+	out, err := json.Marshal(storage)
+	if err != nil || string(out) != `{"dark_elixir":20,"gold":1000}` {
+		t.Errorf("Marshal = %s, %v", out, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"silver": 1}`), &storage); err == nil {
+		t.Error("Unmarshal accepted an unknown currency key")
+	}
+}
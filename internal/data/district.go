@@ -0,0 +1,72 @@
+package data
+
+// District is the typed representation of a single Clan Capital district
+// data file (e.g. data/clan_capital/districts/barbarian_camp.json). Its
+// levels are those of the district's hall: the Capital Hall for Capital
+// Peak and a District Hall everywhere else.
+type District struct {
+	Name                string          `json:"name"`
+	Hall                string          `json:"hall"`
+	Description         string          `json:"description"`
+	CapitalHallRequired int             `json:"capitalHallRequired,omitempty"`
+	UnlockCost          *Cost           `json:"unlockCost,omitempty"`
+	Levels              []DistrictLevel `json:"levels"`
+	Notes               string          `json:"notes,omitempty"`
+	Source              string          `json:"source"`
+	SourceURL           string          `json:"source_url"`
+	SourceLicense       string          `json:"source_license,omitempty"`
+
+	// Extra holds district-specific top-level fields.
+	Extra Extra `json:"-"`
+}
+
+// DistrictLevel holds the stats and upgrade cost for one level of a
+// district's hall.
+type DistrictLevel struct {
+	Level               int    `json:"level"`
+	Hitpoints           int    `json:"hitpoints"`
+	Cost                Cost   `json:"cost"`
+	CapitalHallRequired int    `json:"capitalHallRequired,omitempty"`
+	Notes               string `json:"notes,omitempty"`
+
+	// Extra holds level stats specific to a hall, such as
+	// "districtsUnlocked" on the Capital Hall.
+	Extra Extra `json:"-"`
+}
+
+// InDistrict reports whether the building can be placed in the named
+// district (e.g. "barbarian_camp" or "Barbarian Camp").
+func (b *Building) InDistrict(district string) bool {
+	want := NormalizeName(district)
+	for _, d := range b.Availability.Districts {
+		if NormalizeName(d.District) != want {
+			continue
+		}
+		for _, a := range d.HallLevels {
+			if a.NumberAvailable > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON decodes a district, keeping unmodeled fields in Extra.
+func (d *District) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, d, &d.Extra)
+}
+
+// MarshalJSON encodes a district, including any fields kept in Extra.
+func (d District) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(d, d.Extra)
+}
+
+// UnmarshalJSON decodes a district level, keeping unmodeled stats in Extra.
+func (l *DistrictLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a district level, including any stats kept in Extra.
+func (l DistrictLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
@@ -0,0 +1,185 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// notApplicable is the placeholder used in data files for times that do not
+// apply to a level (e.g. traps that cannot be built at level 1).
+const notApplicable = "N/A"
+
+// durationFields names the JSON keys whose values are Durations.
+var durationFields = map[string]bool{
+	"buildTime":    true,
+	"timeToFill":   true,
+	"catchUpPoint": true,
+	"researchTime": true,
+	"duration":     true,
+}
+
+// IsDurationField reports whether values under the JSON key name are Durations.
+func IsDurationField(name string) bool {
+	return durationFields[name]
+}
+
+// durationUnits lists the units accepted in duration strings, largest first.
+var durationUnits = []struct {
+	suffix  byte
+	seconds int64
+}{
+	{'d', 86400},
+	{'h', 3600},
+	{'m', 60},
+	{'s', 1},
+}
+
+// Duration is a game time such as a build time, written in data files as a
+// free-form string like "1d 12h", "2d 7h 33m 20s" or "N/A".
+//
+// It is encoded as {"raw": "1d 12h", "seconds": 129600}. Seconds is null when
+// the raw value is "N/A".
+type Duration struct {
+	Raw     string
+	Seconds int64
+	Valid   bool // false for "N/A"
+}
+
+// ParseDuration parses a duration string made of day/hour/minute/second parts
+// (e.g. "1d 12h", "5d12h", "30s"). Each unit may appear at most once and units
+// must be in descending order. "N/A" yields an invalid (not applicable) Duration.
+func ParseDuration(s string) (Duration, error) {
+	text := strings.TrimSpace(s)
+	if text == notApplicable {
+		return Duration{Raw: s}, nil
+	}
+	if text == "" {
+		return Duration{}, fmt.Errorf("empty duration")
+	}
+
+	var total int64
+	next := 0 // index into durationUnits of the smallest unit still allowed
+	rest := strings.ReplaceAll(text, " ", "")
+	for rest != "" {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 0 || i == len(rest) {
+			return Duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+		n, err := strconv.ParseInt(rest[:i], 10, 64)
+		if err != nil {
+			return Duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+
+		unit := -1
+		for u := next; u < len(durationUnits); u++ {
+			if durationUnits[u].suffix == rest[i] {
+				unit = u
+				break
+			}
+		}
+		if unit < 0 {
+			return Duration{}, fmt.Errorf("invalid duration %q: unexpected unit %q", s, rest[i])
+		}
+		total += n * durationUnits[unit].seconds
+		next = unit + 1
+		rest = rest[i+1:]
+	}
+
+	return Duration{Raw: s, Seconds: total, Valid: true}, nil
+}
+
+// DurationFromSeconds builds a Duration with a canonical human-readable Raw value.
+func DurationFromSeconds(seconds int64) Duration {
+	d := Duration{Seconds: seconds, Valid: true}
+	d.Raw = d.Human()
+	return d
+}
+
+// Human returns the canonical short form, e.g. "2d 7h 33m 20s" or "0s".
+func (d Duration) Human() string {
+	if !d.Valid {
+		return notApplicable
+	}
+	if d.Seconds == 0 {
+		return "0s"
+	}
+
+	var parts []string
+	rem := d.Seconds
+	for _, u := range durationUnits {
+		if n := rem / u.seconds; n > 0 {
+			parts = append(parts, strconv.FormatInt(n, 10)+string(u.suffix))
+			rem -= n * u.seconds
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ISO8601 returns the duration in ISO 8601 form, e.g. "P1DT12H" or "PT0S".
+// It returns an empty string for "N/A".
+func (d Duration) ISO8601() string {
+	if !d.Valid {
+		return ""
+	}
+	if d.Seconds == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteByte('P')
+	rem := d.Seconds
+	if days := rem / 86400; days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		rem -= days * 86400
+	}
+	if rem > 0 {
+		b.WriteByte('T')
+		for _, u := range durationUnits[1:] {
+			if n := rem / u.seconds; n > 0 {
+				fmt.Fprintf(&b, "%d%c", n, u.suffix-'a'+'A')
+				rem -= n * u.seconds
+			}
+		}
+	}
+	return b.String()
+}
+
+// durationJSON is the encoded form of a Duration.
+type durationJSON struct {
+	Raw     string `json:"raw"`
+	Seconds *int64 `json:"seconds"`
+}
+
+// UnmarshalJSON accepts the duration string used in data files, or the
+// encoded {"raw", "seconds"} object produced by MarshalJSON.
+func (d *Duration) UnmarshalJSON(raw []byte) error {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var obj durationJSON
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return fmt.Errorf("duration must be a string")
+		}
+		s = obj.Raw
+	}
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes the original string alongside its length in seconds.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	out := durationJSON{Raw: d.Raw}
+	if d.Valid {
+		out.Seconds = &d.Seconds
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,114 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		seconds int64
+		valid   bool
+		wantErr bool
+	}{
+		{in: "1d 12h", seconds: 129600, valid: true},
+		{in: "5d12h", seconds: 475200, valid: true},
+		{in: "2d 7h 33m 20s", seconds: 200000, valid: true},
+		{in: "30s", seconds: 30, valid: true},
+		{in: "0s", seconds: 0, valid: true},
+		{in: " 45m ", seconds: 2700, valid: true},
+		{in: "N/A", valid: false},
+		{in: "12h 1d", wantErr: true},
+		{in: "1d1d", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "   ", wantErr: true},
+		{in: "12", wantErr: true},
+		{in: "h", wantErr: true},
+		{in: "3w", wantErr: true},
+		{in: "-1h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDuration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %+v, want error", tt.in, d)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q) error: %v", tt.in, err)
+			continue
+		}
+		if d.Valid != tt.valid || d.Seconds != tt.seconds || d.Raw != tt.in {
+			t.Errorf("ParseDuration(%q) = %+v, want {Raw:%q Seconds:%d Valid:%v}", tt.in, d, tt.in, tt.seconds, tt.valid)
+		}
+	}
+}
+
+func TestDurationFormats(t *testing.T) {
+	tests := []struct {
+		seconds int64
+		human   string
+		iso     string
+	}{
+		{0, "0s", "PT0S"},
+		{30, "30s", "PT30S"},
+		{3600, "1h", "PT1H"},
+		{86400, "1d", "P1D"},
+		{129600, "1d 12h", "P1DT12H"},
+		{200000, "2d 7h 33m 20s", "P2DT7H33M20S"},
+		{86401, "1d 1s", "P1DT1S"},
+	}
+
+	for _, tt := range tests {
+		d := DurationFromSeconds(tt.seconds)
+		if got := d.Human(); got != tt.human {
+			t.Errorf("Human(%d) = %q, want %q", tt.seconds, got, tt.human)
+		}
+		if got := d.ISO8601(); got != tt.iso {
+			t.Errorf("ISO8601(%d) = %q, want %q", tt.seconds, got, tt.iso)
+		}
+	}
+
+	na := Duration{Raw: "N/A"}
+	if got := na.Human(); got != "N/A" {
+		t.Errorf("Human(N/A) = %q, want %q", got, "N/A")
+	}
+	if got := na.ISO8601(); got != "" {
+		t.Errorf("ISO8601(N/A) = %q, want empty", got)
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`"1d 12h"`, `{"raw":"1d 12h","seconds":129600}`},
+		{`"N/A"`, `{"raw":"N/A","seconds":null}`},
+		{`{"raw":"5d12h","seconds":1}`, `{"raw":"5d12h","seconds":475200}`},
+	}
+
+	for _, tt := range tests {
+		var d Duration
+		if err := json.Unmarshal([]byte(tt.in), &d); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", tt.in, err)
+			continue
+		}
+		out, err := json.Marshal(d)
+		if err != nil {
+			t.Errorf("Marshal(%s) error: %v", tt.in, err)
+			continue
+		}
+		if string(out) != tt.want {
+			t.Errorf("round trip of %s = %s, want %s", tt.in, out, tt.want)
+		}
+	}
+
+	var d Duration
+	if err := json.Unmarshal([]byte(`12`), &d); err == nil {
+		t.Error("Unmarshal(12) succeeded, want error")
+	}
+}
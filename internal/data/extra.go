@@ -0,0 +1,126 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extra holds JSON fields that are not modeled explicitly by a typed struct.
+// Data files vary between buildings (e.g. "capacity", "productionRate",
+// "springCapacity"), so anything the model does not name is kept here and
+// written back out verbatim when the value is encoded.
+type Extra map[string]json.RawMessage
+
+// knownKeys caches the JSON field names declared on each struct type.
+var knownKeys sync.Map // map[reflect.Type]map[string]bool
+
+// plainTypes caches, for each model struct type, an unnamed struct type with
+// the same fields. It has no methods, so encoding/json uses the default
+// struct encoding for it instead of recursing into the model's own
+// (un)marshalers.
+var plainTypes sync.Map // map[reflect.Type]reflect.Type
+
+// fieldNames returns the set of JSON keys declared by the struct type t.
+func fieldNames(t reflect.Type) map[string]bool {
+	if cached, ok := knownKeys.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		names[name] = true
+	}
+	knownKeys.Store(t, names)
+	return names
+}
+
+// plainType returns the method-less counterpart of the struct type t.
+func plainType(t reflect.Type) reflect.Type {
+	if cached, ok := plainTypes.Load(t); ok {
+		return cached.(reflect.Type)
+	}
+
+	fields := make([]reflect.StructField, t.NumField())
+	for i := range fields {
+		fields[i] = t.Field(i)
+	}
+	plain := reflect.StructOf(fields)
+	plainTypes.Store(t, plain)
+	return plain
+}
+
+// unmarshalWithExtra implements UnmarshalJSON for a model struct with an
+// Extra field: it decodes raw into v and stores every top-level key that the
+// struct does not declare in *extra, which should point into v:
+//
+//	func (t *Troop) UnmarshalJSON(raw []byte) error {
+//		return unmarshalWithExtra(raw, t, &t.Extra)
+//	}
+func unmarshalWithExtra[T any](raw []byte, v *T, extra *Extra) error {
+	t := reflect.TypeFor[T]()
+	plain := reflect.New(plainType(t))
+	if err := json.Unmarshal(raw, plain.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(v).Elem().Set(plain.Elem().Convert(t))
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return err
+	}
+
+	known := fieldNames(t)
+	*extra = nil
+	for k, val := range all {
+		if known[k] {
+			continue
+		}
+		if *extra == nil {
+			*extra = make(Extra)
+		}
+		(*extra)[k] = val
+	}
+	return nil
+}
+
+// marshalWithExtra implements MarshalJSON for a model struct with an Extra
+// field, appending the extra fields after the declared ones in sorted key
+// order.
+func marshalWithExtra[T any](v T, extra Extra) ([]byte, error) {
+	plain := reflect.ValueOf(v).Convert(plainType(reflect.TypeFor[T]()))
+	out, err := json.Marshal(plain.Interface())
+	if err != nil || len(extra) == 0 {
+		return out, err
+	}
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(out[:len(out)-1]) // drop closing brace
+	empty := len(out) == 2
+	for _, k := range keys {
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
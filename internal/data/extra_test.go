@@ -0,0 +1,45 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtraRoundTrip(t *testing.T) {
+	raw := `{"name":"Barbarian","housingSpace":1,"jumpsOverWalls":false,"levels":[{"level":1,"hitpoints":45,"damagePerShot":9}]}`
+
+	var troop Troop
+	if err := json.Unmarshal([]byte(raw), &troop); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if troop.Name != "Barbarian" || troop.HousingSpace != 1 || len(troop.Levels) != 1 || troop.Levels[0].Hitpoints != 45 {
+		t.Errorf("declared fields = %+v", troop)
+	}
+	if len(troop.Extra) != 1 || string(troop.Extra["jumpsOverWalls"]) != "false" {
+		t.Errorf("Extra = %s", troop.Extra)
+	}
+	if string(troop.Levels[0].Extra["damagePerShot"]) != "9" {
+		t.Errorf("level Extra = %s", troop.Levels[0].Extra)
+	}
+
+	out, err := json.Marshal(troop)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var back map[string]interface{}
+	if err := json.Unmarshal(out, &back); err != nil {
+		t.Fatalf("re-decoding %s: %v", out, err)
+	}
+	if back["jumpsOverWalls"] != false || back["name"] != "Barbarian" {
+		t.Errorf("Marshal = %s", out)
+	}
+	level := back["levels"].([]interface{})[0].(map[string]interface{})
+	if level["damagePerShot"] != 9.0 {
+		t.Errorf("level Marshal = %v", level)
+	}
+
+	// Decoding again replaces the previous extra fields.
+	if err := json.Unmarshal([]byte(`{"name":"Archer"}`), &troop); err != nil || troop.Extra != nil {
+		t.Errorf("second Unmarshal = %v, Extra %s", err, troop.Extra)
+	}
+}
@@ -0,0 +1,74 @@
+package data
+
+import "strings"
+
+// BuildingFilter selects buildings in a category listing.
+// Zero-valued fields match every building.
+type BuildingFilter struct {
+	Target         string // attack.targetTypes contains this value (e.g. "Air")
+	DamageType     string // attack.damageType contains this text (e.g. "Splash")
+	MinTownHall    int    // unlocked at this Town Hall level or later
+	MaxTownHall    int    // unlocked at this Town Hall level or earlier
+	HasSupercharge *bool  // has (or lacks) supercharge levels
+	Width, Height  int    // footprint in tiles
+}
+
+// IsZero reports whether the filter matches every building.
+func (f BuildingFilter) IsZero() bool {
+	return f == BuildingFilter{}
+}
+
+// Match reports whether b satisfies every condition of the filter.
+// Text comparisons are case-insensitive.
+func (f BuildingFilter) Match(b *Building) bool {
+	if f.Target != "" && !hasTarget(b, f.Target) {
+		return false
+	}
+	if f.DamageType != "" {
+		if b.Attack == nil || !containsFold(b.Attack.DamageType, f.DamageType) {
+			return false
+		}
+	}
+	if f.MinTownHall > 0 || f.MaxTownHall > 0 {
+		th := b.UnlockTownHall()
+		if th == 0 || (f.MinTownHall > 0 && th < f.MinTownHall) || (f.MaxTownHall > 0 && th > f.MaxTownHall) {
+			return false
+		}
+	}
+	if f.HasSupercharge != nil && (len(b.Supercharges) > 0) != *f.HasSupercharge {
+		return false
+	}
+	if f.Width > 0 && (b.Size.Width != f.Width || b.Size.Height != f.Height) {
+		return false
+	}
+	return true
+}
+
+// UnlockTownHall returns the lowest Town Hall level at which the building can
+// be placed, or 0 if it is never available.
+func (b *Building) UnlockTownHall() int {
+	for _, a := range b.Availability.TownHallLevels {
+		if a.NumberAvailable > 0 {
+			return a.TownHall
+		}
+	}
+	return 0
+}
+
+// hasTarget reports whether the building's attack can target the given type.
+func hasTarget(b *Building, target string) bool {
+	if b.Attack == nil {
+		return false
+	}
+	for _, t := range b.Attack.TargetTypes {
+		if strings.EqualFold(t, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether substr is within s, ignoring case.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,123 @@
+package data
+
+// Hero is the typed representation of a single hero data file
+// (e.g. data/home_village/heroes/barbarian_king.json).
+type Hero struct {
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	MovementSpeed  float64      `json:"movementSpeed"`
+	AttackSpeed    float64      `json:"attackSpeed"`
+	Range          *Range       `json:"range,omitempty"`
+	FavoriteTarget string       `json:"favoriteTarget"`
+	TargetTypes    []string     `json:"targetTypes"`
+	Ability        *HeroAbility `json:"ability,omitempty"`
+	Levels         []HeroLevel  `json:"levels"`
+	Notes          string       `json:"notes,omitempty"`
+	Source         string       `json:"source"`
+	SourceURL      string       `json:"source_url"`
+	SourceLicense  string       `json:"source_license,omitempty"`
+
+	// UnlockedBy is the Hero Hall level that unlocks the hero. It is
+	// resolved from the building data, not read from the hero file.
+	UnlockedBy *Unlock `json:"unlockedBy,omitempty"`
+
+	// Extra holds hero-specific top-level fields.
+	Extra Extra `json:"-"`
+}
+
+// HeroAbility is an ability a hero has without any equipment.
+type HeroAbility struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// HeroLevel holds the stats and upgrade cost for one hero level.
+type HeroLevel struct {
+	Level           int       `json:"level"`
+	DamagePerSecond float64   `json:"damagePerSecond"`
+	Hitpoints       int       `json:"hitpoints"`
+	Cost            *Cost     `json:"cost,omitempty"`
+	BuildTime       *Duration `json:"buildTime,omitempty"`
+	HeroHallLevel   int       `json:"heroHallLevelRequired,omitempty"`
+	BuilderHall     int       `json:"builderHallRequired,omitempty"`
+	Notes           string    `json:"notes,omitempty"`
+
+	// Extra holds level stats specific to a hero, such as "regenerationTime".
+	Extra Extra `json:"-"`
+}
+
+// Equipment is the typed representation of a single hero equipment data
+// file (e.g. data/home_village/equipment/giant_gauntlet.json).
+type Equipment struct {
+	Name          string           `json:"name"`
+	Hero          string           `json:"hero"`
+	Rarity        string           `json:"rarity"`
+	Description   string           `json:"description"`
+	Ability       string           `json:"ability,omitempty"`
+	Levels        []EquipmentLevel `json:"levels"`
+	Notes         string           `json:"notes,omitempty"`
+	Source        string           `json:"source"`
+	SourceURL     string           `json:"source_url"`
+	SourceLicense string           `json:"source_license,omitempty"`
+
+	// UnlockedBy is the Blacksmith level that unlocks the equipment. It is
+	// nil for equipment that comes with its hero.
+	UnlockedBy *Unlock `json:"unlockedBy,omitempty"`
+
+	// Extra holds equipment-specific top-level fields.
+	Extra Extra `json:"-"`
+}
+
+// EquipmentLevel holds the effect values and ore cost for one equipment
+// level. Epic equipment costs several ores at once, so Cost lists every
+// price that must be paid, unlike the alternatives of a building Cost.
+type EquipmentLevel struct {
+	Level           int     `json:"level"`
+	Cost            []Price `json:"cost,omitempty"`
+	BlacksmithLevel int     `json:"blacksmithLevelRequired"`
+	Notes           string  `json:"notes,omitempty"`
+
+	// Extra holds the stat boosts and ability values of the level, such as
+	// "hitpointIncrease" or "damageIncrease".
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a hero, keeping unmodeled fields in Extra.
+func (h *Hero) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, h, &h.Extra)
+}
+
+// MarshalJSON encodes a hero, including any fields kept in Extra.
+func (h Hero) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(h, h.Extra)
+}
+
+// UnmarshalJSON decodes a hero level, keeping unmodeled stats in Extra.
+func (l *HeroLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a hero level, including any stats kept in Extra.
+func (l HeroLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
+
+// UnmarshalJSON decodes equipment, keeping unmodeled fields in Extra.
+func (e *Equipment) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, e, &e.Extra)
+}
+
+// MarshalJSON encodes equipment, including any fields kept in Extra.
+func (e Equipment) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(e, e.Extra)
+}
+
+// UnmarshalJSON decodes an equipment level, keeping effect values in Extra.
+func (l *EquipmentLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes an equipment level, including effect values kept in Extra.
+func (l EquipmentLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
@@ -1,4 +1,4 @@
-// Package data handles loading and parsing JSON data files from the filesystem.
+// Package data handles loading and parsing JSON data files from a file system.
 // It provides methods to list categories, enumerate items, and retrieve individual
 // data entries while filtering out template files.
 package data
@@ -6,19 +6,27 @@ package data
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
 	"strings"
+	"time"
 )
 
-// Loader reads and serves JSON data from a base directory.
+// Loader reads and serves JSON data from a file system whose root holds one
+// directory per base. The file system is either the snapshot embedded in the
+// binary or an on-disk tree (see os.DirFS).
 type Loader struct {
-	baseDir string
+	// AllowMissingKinds lets Validate pass references to kinds that have no
+	// data files yet, so a base can be filled in one kind at a time.
+	// Unchecked reports them instead.
+	AllowMissingKinds bool
+
+	fsys   fs.FS
+	graphs map[string]*Graph // by base, kept by Graph
 }
 
-// NewLoader creates a Loader rooted at the given base directory.
-func NewLoader(baseDir string) *Loader {
-	return &Loader{baseDir: baseDir}
+// NewLoader creates a Loader reading from the given file system.
+func NewLoader(fsys fs.FS) *Loader {
+	return &Loader{fsys: fsys, graphs: make(map[string]*Graph)}
 }
 
 // CategoryInfo contains metadata about a data category (subdirectory).
@@ -34,12 +42,27 @@ type ItemSummary struct {
 	Path string `json:"path"`
 }
 
+// ListBases returns the names of the top-level base directories
+// (e.g. "home_village").
+func (l *Loader) ListBases() ([]string, error) {
+	entries, err := fs.ReadDir(l.fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("data directory not found")
+	}
+
+	var bases []string
+	for _, e := range entries {
+		if e.IsDir() {
+			bases = append(bases, e.Name())
+		}
+	}
+	return bases, nil
+}
+
 // ListCategories returns all subdirectories within the given sub-path,
 // along with the count of JSON data files in each.
 func (l *Loader) ListCategories(subPath string) ([]CategoryInfo, error) {
-	dir := filepath.Join(l.baseDir, filepath.FromSlash(subPath))
-
-	entries, err := os.ReadDir(dir)
+	entries, err := fs.ReadDir(l.fsys, subPath)
 	if err != nil {
 		return nil, fmt.Errorf("directory not found: %s", subPath)
 	}
@@ -78,25 +101,40 @@ func (l *Loader) ListItems(subPath string) ([]ItemSummary, error) {
 	return items, nil
 }
 
-// GetItem reads and validates a single JSON file at the given sub-path.
-// The sub-path should NOT include the .json extension.
-func (l *Loader) GetItem(subPath string) (json.RawMessage, error) {
-	target := filepath.Join(l.baseDir, filepath.FromSlash(subPath)+".json")
-
-	// Prevent directory traversal by verifying resolved path stays within baseDir.
-	absBase, err := filepath.Abs(l.baseDir)
+// ListKindItems returns every data file of a kind (e.g. "home_village/spells"),
+// whether the kind keeps its files in category subdirectories or directly in
+// its own directory.
+func (l *Loader) ListKindItems(kindPath string) ([]ItemSummary, error) {
+	items, err := l.ListItems(kindPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve base directory")
+		return nil, err
 	}
-	absTarget, err := filepath.Abs(target)
+
+	categories, err := l.ListCategories(kindPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve target path")
+		return nil, err
 	}
-	if !strings.HasPrefix(absTarget, absBase+string(filepath.Separator)) {
+	for _, c := range categories {
+		catItems, err := l.ListItems(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, catItems...)
+	}
+	return items, nil
+}
+
+// GetItem reads and validates a single JSON file at the given sub-path.
+// The sub-path should NOT include the .json extension.
+func (l *Loader) GetItem(subPath string) (json.RawMessage, error) {
+	target := subPath + ".json"
+
+	// Prevent directory traversal: fs.FS paths may not contain ".." or be rooted.
+	if !fs.ValidPath(target) {
 		return nil, fmt.Errorf("invalid path: %s", subPath)
 	}
 
-	raw, err := os.ReadFile(target)
+	raw, err := fs.ReadFile(l.fsys, target)
 	if err != nil {
 		return nil, fmt.Errorf("item not found: %s", subPath)
 	}
@@ -108,11 +146,31 @@ func (l *Loader) GetItem(subPath string) (json.RawMessage, error) {
 	return json.RawMessage(raw), nil
 }
 
+// ModTime returns the modification time of the data file at the given
+// sub-path (without the .json extension) or, failing that, of the directory.
+// File systems without modification times, such as embedded ones, report
+// the zero time.
+func (l *Loader) ModTime(subPath string) (time.Time, error) {
+	info, err := fs.Stat(l.fsys, subPath+".json")
+	if err != nil {
+		if info, err = fs.Stat(l.fsys, subPath); err != nil {
+			return time.Time{}, fmt.Errorf("item not found: %s", subPath)
+		}
+	}
+	return info.ModTime(), nil
+}
+
+// BuildingRef is a typed building together with where it was loaded from.
+type BuildingRef struct {
+	Category string
+	Name     string
+	Path     string
+	Building *Building
+}
+
 // listJSONFiles returns filenames of non-template .json files in a directory.
 func (l *Loader) listJSONFiles(subPath string) ([]string, error) {
-	dir := filepath.Join(l.baseDir, filepath.FromSlash(subPath))
-
-	entries, err := os.ReadDir(dir)
+	entries, err := fs.ReadDir(l.fsys, subPath)
 	if err != nil {
 		return nil, fmt.Errorf("directory not found: %s", subPath)
 	}
@@ -0,0 +1,59 @@
+package data
+
+// Pet is the typed representation of a single pet data file
+// (e.g. data/home_village/pets/electro_owl.json).
+type Pet struct {
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	MovementSpeed  float64    `json:"movementSpeed"`
+	AttackSpeed    float64    `json:"attackSpeed"`
+	Range          *Range     `json:"range,omitempty"`
+	FavoriteTarget string     `json:"favoriteTarget,omitempty"`
+	TargetTypes    []string   `json:"targetTypes,omitempty"`
+	Levels         []PetLevel `json:"levels"`
+	Notes          string     `json:"notes,omitempty"`
+	Source         string     `json:"source"`
+	SourceURL      string     `json:"source_url"`
+	SourceLicense  string     `json:"source_license,omitempty"`
+
+	// UnlockedBy is the Pet House level that unlocks the pet. It is
+	// resolved from the building data, not read from the pet file.
+	UnlockedBy *Unlock `json:"unlockedBy,omitempty"`
+
+	// Extra holds pet-specific top-level fields.
+	Extra Extra `json:"-"`
+}
+
+// PetLevel holds the stats and research cost for one pet level.
+type PetLevel struct {
+	Level           int       `json:"level"`
+	DamagePerSecond float64   `json:"damagePerSecond,omitempty"`
+	Hitpoints       int       `json:"hitpoints"`
+	ResearchCost    *Cost     `json:"researchCost,omitempty"`
+	ResearchTime    *Duration `json:"researchTime,omitempty"`
+	PetHouseLevel   int       `json:"petHouseLevelRequired"`
+	Notes           string    `json:"notes,omitempty"`
+
+	// Extra holds level stats specific to a pet, such as "healingPerSecond".
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a pet, keeping unmodeled fields in Extra.
+func (p *Pet) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, p, &p.Extra)
+}
+
+// MarshalJSON encodes a pet, including any fields kept in Extra.
+func (p Pet) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(p, p.Extra)
+}
+
+// UnmarshalJSON decodes a pet level, keeping unmodeled stats in Extra.
+func (l *PetLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a pet level, including any stats kept in Extra.
+func (l PetLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
@@ -0,0 +1,360 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// requirementFields maps the level fields that hold a required level of
+// another building to that building's name in each base. Bases missing from
+// a field's map do not use it.
+var requirementFields = map[string]map[string]string{
+	"laboratoryLevelRequired": {"home_village": "Laboratory", "builder_base": "Star Laboratory"},
+	"laboratoryLevelCap":      {"home_village": "Laboratory"},
+	"heroHallLevelRequired":   {"home_village": "Hero Hall"},
+	"blacksmithLevelRequired": {"home_village": "Blacksmith"},
+	"petHouseLevelRequired":   {"home_village": "Pet House"},
+	"builderHallRequired":     {"builder_base": "Builder Hall"},
+	"capitalHallRequired":     {"clan_capital": "Capital Hall"},
+}
+
+// unlockerFields are level fields that require a level of whichever building
+// unlocks the unit (e.g. the raid troops of each Clan Capital barracks).
+var unlockerFields = map[string]bool{
+	"barracksLevelRequired":     true,
+	"spellFactoryLevelRequired": true,
+}
+
+// Edge is one resolved relationship between two entities.
+type Edge struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+
+	// Field is the data field the relationship was read from.
+	Field string `json:"field"`
+
+	// Level is the level of the entity the edge belongs to, and TargetLevel
+	// the level of the other entity. Either is omitted when it does not apply.
+	Level       int `json:"level,omitempty"`
+	TargetLevel int `json:"targetLevel,omitempty"`
+}
+
+// Relations lists what an entity unlocks, what unlocks it, and the levels
+// of other entities it requires.
+type Relations struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Unlocks    []Edge `json:"unlocks"`
+	UnlockedBy []Edge `json:"unlockedBy"`
+	Requires   []Edge `json:"requires"`
+}
+
+// Graph holds the resolved relationships between the entities of one base,
+// keyed by API path.
+type Graph struct {
+	base      string
+	nodes     map[string]*Relations
+	dangling  []Violation
+	unchecked map[string]int // references by target kind, for kinds with no data files
+}
+
+// Lookup returns the relations of the entity at an API path
+// (e.g. "/api/home_village/buildings/army/laboratory").
+func (g *Graph) Lookup(path string) (*Relations, bool) {
+	r, ok := g.nodes[path]
+	return r, ok
+}
+
+// Dangling reports the references that point at nothing: unlock fields
+// naming unknown units, and requirements for buildings or levels that do not
+// exist. References to kinds with no data files yet are not reported, so a
+// base can be filled in one kind at a time; Unchecked counts them instead.
+func (g *Graph) Dangling() []Violation {
+	return g.dangling
+}
+
+// Units indexes the units of the base that buildings can unlock, so unlock
+// fields can be resolved to API paths.
+func (g *Graph) Units() UnitIndex {
+	unitKinds := make(map[string]bool)
+	for _, kind := range unlockKinds {
+		unitKinds[kind] = true
+	}
+
+	ix := make(UnitIndex)
+	for _, rel := range g.nodes {
+		if unitKinds[rel.Kind] {
+			ix[unlockKey(rel.Kind, rel.Name)] = rel.Path
+		}
+	}
+	return ix
+}
+
+// Unchecked reports, as one warning per kind, the references left unchecked
+// by Dangling because the kind they point at has no data files yet.
+func (g *Graph) Unchecked() []Violation {
+	kinds := make([]string, 0, len(g.unchecked))
+	for kind := range g.unchecked {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	warnings := make([]Violation, 0, len(kinds))
+	for _, kind := range kinds {
+		warnings = append(warnings, Violation{
+			File:    g.base + "/" + kind,
+			Path:    "$",
+			Message: fmt.Sprintf("no data files; %d reference(s) to %s were not checked", g.unchecked[kind], kind),
+		})
+	}
+	return warnings
+}
+
+// graphNode is an entity read while building a Graph.
+type graphNode struct {
+	rel        *Relations
+	file       string
+	levels     []map[string]interface{}
+	levelsPath string
+}
+
+// Graph returns the relations graph of a base, building it on first use.
+// The Loader keeps it, so Validate, Unchecked and loading share one graph.
+func (l *Loader) Graph(base string) (*Graph, error) {
+	if g, ok := l.graphs[base]; ok {
+		return g, nil
+	}
+	g, err := l.BuildGraph(base)
+	if err != nil {
+		return nil, err
+	}
+	l.graphs[base] = g
+	return g, nil
+}
+
+// BuildGraph reads every entity of a base and resolves the relationships
+// between them.
+func (l *Loader) BuildGraph(base string) (*Graph, error) {
+	kinds, err := l.ListCategories(base)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{base: base, nodes: make(map[string]*Relations), unchecked: make(map[string]int)}
+	var nodes []*graphNode
+	byName := make(map[string]*graphNode)
+	populated := make(map[string]bool)
+	maxTownHall := 0
+
+	for _, k := range kinds {
+		// The Town Hall is not served under its data directory, so it is
+		// not a node; requirements on it link to /api/{base}/townhall/{level}.
+		if k.Name == "town_hall" {
+			continue
+		}
+		items, err := l.ListKindItems(k.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			raw, err := l.GetItem(it.Path)
+			if err != nil {
+				return nil, err
+			}
+			doc, err := decodeDocument(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid data in %s: %w", it.Path, err)
+			}
+			obj, _ := doc.(map[string]interface{})
+			name, _ := obj["name"].(string)
+
+			n := &graphNode{
+				rel: &Relations{
+					Kind:       k.Name,
+					Name:       name,
+					Path:       "/api/" + it.Path,
+					Unlocks:    []Edge{},
+					UnlockedBy: []Edge{},
+					Requires:   []Edge{},
+				},
+				file: it.Path + ".json",
+			}
+			n.levels, n.levelsPath = levelTable(obj)
+			nodes = append(nodes, n)
+			byName[unlockKey(k.Name, name)] = n
+			g.nodes[n.rel.Path] = n.rel
+			populated[k.Name] = true
+
+			if k.Name == "buildings" {
+				if th := maxAvailableTownHall(obj); th > maxTownHall {
+					maxTownHall = th
+				}
+			}
+		}
+	}
+
+	report := func(n *graphNode, i int, field, message string) {
+		g.dangling = append(g.dangling, Violation{
+			File:    n.file,
+			Path:    fmt.Sprintf("%s[%d].%s", n.levelsPath, i, field),
+			Message: message,
+		})
+	}
+
+	// Unlocks come first, since unlocker requirements follow them backwards.
+	for _, n := range nodes {
+		for i, level := range n.levels {
+			for _, field := range sortedKeys(level) {
+				kind, ok := unlockKinds[field]
+				if !ok {
+					continue
+				}
+				raw, _ := json.Marshal(level[field])
+				for _, name := range UnlockedNames(raw) {
+					target := byName[unlockKey(kind, name)]
+					if target == nil {
+						if populated[kind] {
+							report(n, i, field, fmt.Sprintf("%q does not match any %s", name, kind))
+						} else {
+							g.unchecked[kind]++
+						}
+						continue
+					}
+					lvl := levelNumber(level)
+					n.rel.Unlocks = append(n.rel.Unlocks, Edge{
+						Kind: kind, Name: target.rel.Name, Path: target.rel.Path, Field: field, Level: lvl,
+					})
+					target.rel.UnlockedBy = append(target.rel.UnlockedBy, Edge{
+						Kind: n.rel.Kind, Name: n.rel.Name, Path: n.rel.Path, Field: field, TargetLevel: lvl,
+					})
+				}
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		for i, level := range n.levels {
+			for _, field := range sortedKeys(level) {
+				required, ok := intValue(level[field])
+				if !ok || required < 1 {
+					continue
+				}
+				edge := Edge{Field: field, Level: levelNumber(level), TargetLevel: required}
+
+				var target *graphNode
+				switch {
+				case field == "townHallRequired":
+					if maxTownHall > 0 && required > maxTownHall {
+						report(n, i, field, fmt.Sprintf("there is no Town Hall level %d", required))
+						continue
+					}
+					edge.Kind, edge.Name = "townhall", "Town Hall"
+					edge.Path = "/api/" + base + "/townhall/" + strconv.Itoa(required)
+					n.rel.Requires = append(n.rel.Requires, edge)
+					continue
+				case unlockerFields[field]:
+					if len(n.rel.UnlockedBy) == 0 {
+						if populated["buildings"] {
+							report(n, i, field, "no building unlocks "+n.rel.Name)
+						} else {
+							g.unchecked["buildings"]++
+						}
+						continue
+					}
+					target = byName[unlockKey("buildings", n.rel.UnlockedBy[0].Name)]
+				default:
+					building, ok := requirementFields[field][base]
+					if !ok {
+						continue
+					}
+					if target = byName[unlockKey("buildings", building)]; target == nil {
+						if populated["buildings"] {
+							report(n, i, field, "there is no "+building+" building")
+						} else {
+							g.unchecked["buildings"]++
+						}
+						continue
+					}
+				}
+				// A hall's requirement on its own level (the Builder Hall's
+				// builderHallRequired) is not a relationship.
+				if target == n {
+					continue
+				}
+
+				if required > len(target.levels) {
+					report(n, i, field, fmt.Sprintf("%s has no level %d", target.rel.Name, required))
+					continue
+				}
+				edge.Kind, edge.Name, edge.Path = target.rel.Kind, target.rel.Name, target.rel.Path
+				n.rel.Requires = append(n.rel.Requires, edge)
+			}
+		}
+	}
+	return g, nil
+}
+
+// levelTable returns an entity's level entries and their JSON path. Like
+// Building.UpgradeLevels, it falls back to the first mode's levels.
+func levelTable(obj map[string]interface{}) ([]map[string]interface{}, string) {
+	list, _ := obj["levels"].([]interface{})
+	path := "$.levels"
+	if len(list) == 0 {
+		if modes, ok := obj["modes"].([]interface{}); ok && len(modes) > 0 {
+			mode, _ := modes[0].(map[string]interface{})
+			list, _ = mode["levels"].([]interface{})
+			path = "$.modes[0].levels"
+		}
+	}
+
+	levels := make([]map[string]interface{}, 0, len(list))
+	for _, it := range list {
+		level, _ := it.(map[string]interface{})
+		levels = append(levels, level)
+	}
+	return levels, path
+}
+
+// maxAvailableTownHall returns the highest Town Hall level in a building's
+// availability table.
+func maxAvailableTownHall(obj map[string]interface{}) int {
+	avail, _ := obj["availability"].(map[string]interface{})
+	list, _ := avail["townHallLevels"].([]interface{})
+	max := 0
+	for _, it := range list {
+		entry, _ := it.(map[string]interface{})
+		if th, ok := intValue(entry["townHall"]); ok && th > max {
+			max = th
+		}
+	}
+	return max
+}
+
+// levelNumber returns the "level" of a level entry, or 0 if it has none.
+func levelNumber(level map[string]interface{}) int {
+	n, _ := intValue(level["level"])
+	return n
+}
+
+// intValue converts a decoded JSON number to an int.
+func intValue(v interface{}) (int, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := n.Int64()
+	return int(i), err == nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,266 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// schema describes the expected shape of a JSON value, derived from a
+// category's template.json.
+//
+// Templates are JSON with line comments. A key is optional when it is
+// preceded by a comment starting with "Optional", or when its example value
+// is a string starting with "Optional". Every other key in a template is
+// required. Keys not mentioned in the template are allowed.
+type schema struct {
+	kind     string // "object", "array", "string", "number" or "boolean"
+	optional bool
+	fields   map[string]*schema
+	items    *schema
+}
+
+// parseTemplate builds a schema from the raw bytes of a template.json file.
+// It returns (nil, nil) for an empty template.
+func parseTemplate(raw []byte) (*schema, error) {
+	stripped, optionalAt := stripComments(raw)
+	if len(bytes.TrimSpace(stripped)) == 0 {
+		return nil, nil
+	}
+
+	p := &templateParser{
+		dec:        json.NewDecoder(bytes.NewReader(stripped)),
+		optionalAt: optionalAt,
+	}
+	p.dec.UseNumber()
+
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return p.value(tok)
+}
+
+// stripComments blanks out // line comments that are not inside strings,
+// preserving byte offsets. It returns the cleaned bytes and the offsets of
+// every comment whose text starts with "Optional".
+func stripComments(raw []byte) ([]byte, []int64) {
+	out := make([]byte, len(raw))
+	copy(out, raw)
+
+	var optionalAt []int64
+	inString := false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			end := bytes.IndexByte(out[i:], '\n')
+			if end < 0 {
+				end = len(out) - i
+			}
+			text := strings.TrimSpace(string(out[i+2 : i+end]))
+			if strings.HasPrefix(text, "Optional") {
+				optionalAt = append(optionalAt, int64(i))
+			}
+			for j := i; j < i+end; j++ {
+				out[j] = ' '
+			}
+			i += end - 1
+		}
+	}
+	return out, optionalAt
+}
+
+// templateParser walks template tokens and records which keys are optional.
+type templateParser struct {
+	dec        *json.Decoder
+	optionalAt []int64
+}
+
+// value builds the schema for the value beginning with tok.
+func (p *templateParser) value(tok json.Token) (*schema, error) {
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return p.object()
+		}
+		return p.array()
+	case string:
+		return &schema{kind: "string", optional: strings.HasPrefix(t, "Optional")}, nil
+	case json.Number:
+		return &schema{kind: "number"}, nil
+	case bool:
+		return &schema{kind: "boolean"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported template value %v", tok)
+	}
+}
+
+// object parses the members of a template object after its opening brace.
+func (p *templateParser) object() (*schema, error) {
+	s := &schema{kind: "object", fields: make(map[string]*schema)}
+	for {
+		// Record the offset before More, which skips the blanked comments.
+		start := p.dec.InputOffset()
+		if !p.dec.More() {
+			break
+		}
+		keyTok, err := p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected object key, got %v", keyTok)
+		}
+		optional := p.commentBetween(start, p.dec.InputOffset())
+
+		tok, err := p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		field, err := p.value(tok)
+		if err != nil {
+			return nil, err
+		}
+		field.optional = field.optional || optional
+		s.fields[key] = field
+	}
+	_, err := p.dec.Token() // closing brace
+	return s, err
+}
+
+// array parses a template array. The first element describes every item.
+func (p *templateParser) array() (*schema, error) {
+	s := &schema{kind: "array"}
+	for p.dec.More() {
+		tok, err := p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		item, err := p.value(tok)
+		if err != nil {
+			return nil, err
+		}
+		if s.items == nil {
+			s.items = item
+		}
+	}
+	_, err := p.dec.Token() // closing bracket
+	return s, err
+}
+
+// commentBetween reports whether an "Optional" comment lies in [from, to).
+func (p *templateParser) commentBetween(from, to int64) bool {
+	i := sort.Search(len(p.optionalAt), func(i int) bool { return p.optionalAt[i] >= from })
+	return i < len(p.optionalAt) && p.optionalAt[i] < to
+}
+
+// check validates v against the schema, calling report for each problem.
+// Values must be decoded with json.Decoder.UseNumber.
+func (s *schema) check(path string, v interface{}, report func(path, message string)) {
+	got := jsonKind(v)
+	if got != s.kind && !(s.kind == "number" && isNumericObject(v)) {
+		report(path, fmt.Sprintf("expected %s, got %s", s.kind, describe(v)))
+		return
+	}
+
+	switch s.kind {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return // numeric object standing in for a number
+		}
+		keys := make([]string, 0, len(s.fields))
+		for k := range s.fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			field := s.fields[k]
+			val, present := obj[k]
+			if !present || val == nil {
+				if !field.optional {
+					report(path+"."+k, "missing required field")
+				}
+				continue
+			}
+			field.check(path+"."+k, val, report)
+		}
+	case "array":
+		if s.items == nil {
+			return
+		}
+		for i, item := range v.([]interface{}) {
+			s.items.check(fmt.Sprintf("%s[%d]", path, i), item, report)
+		}
+	}
+}
+
+// jsonKind returns the schema kind name for a decoded JSON value.
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// isNumericObject reports whether v is an object whose values are all numbers.
+// Stats that vary by phase or have a lower bound (e.g. Mortar range
+// {"min": 4, "max": 11}) are written this way in place of a single number.
+func isNumericObject(v interface{}) bool {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		return false
+	}
+	for _, val := range obj {
+		if _, ok := val.(json.Number); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// describe renders a value's kind for error messages, quoting short strings.
+func describe(v interface{}) string {
+	if s, ok := v.(string); ok && len(s) <= 32 {
+		return fmt.Sprintf("string %q", s)
+	}
+	return jsonKind(v)
+}
+
+// decodeDocument decodes a data file into generic values with json.Number.
+func decodeDocument(raw []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return v, nil
+}
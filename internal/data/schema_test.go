@@ -0,0 +1,119 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testTemplate = `{
+  // The display name
+  "name": "Cannon",
+  // Optional: only some buildings have one
+  "description": "text",
+  "notes": "Optional notes",
+  "size": {"width": 3, "height": 3},
+  "levels": [
+    {
+      "level": 1,
+      "hitpoints": 420, // trailing comment with "quotes"
+      "url": "http://example.com//not-a-comment"
+    }
+  ]
+}`
+
+func TestParseTemplate(t *testing.T) {
+	s, err := parseTemplate([]byte(testTemplate))
+	if err != nil {
+		t.Fatalf("parseTemplate error: %v", err)
+	}
+
+	if s.kind != "object" {
+		t.Fatalf("kind = %q, want object", s.kind)
+	}
+	for key, optional := range map[string]bool{
+		"name":        false,
+		"description": true,
+		"notes":       true,
+		"size":        false,
+		"levels":      false,
+	} {
+		f, ok := s.fields[key]
+		if !ok {
+			t.Errorf("field %q missing from schema", key)
+			continue
+		}
+		if f.optional != optional {
+			t.Errorf("field %q optional = %v, want %v", key, f.optional, optional)
+		}
+	}
+
+	levels := s.fields["levels"]
+	if levels.kind != "array" || levels.items == nil || levels.items.kind != "object" {
+		t.Fatalf("levels schema = %+v, want array of objects", levels)
+	}
+	if got := levels.items.fields["url"]; got == nil || got.kind != "string" {
+		t.Errorf("url inside a string was treated as a comment")
+	}
+
+	if s, err := parseTemplate([]byte("  // only a comment\n")); s != nil || err != nil {
+		t.Errorf("parseTemplate(empty) = %v, %v, want nil, nil", s, err)
+	}
+	if _, err := parseTemplate([]byte(`{"name": null}`)); err == nil {
+		t.Error("parseTemplate accepted a null example value")
+	}
+}
+
+func TestSchemaCheck(t *testing.T) {
+	s, err := parseTemplate([]byte(testTemplate))
+	if err != nil {
+		t.Fatalf("parseTemplate error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		want []string // reported paths
+	}{
+		{
+			name: "valid",
+			doc:  `{"name": "Cannon", "size": {"width": 3, "height": 3}, "levels": [{"level": 1, "hitpoints": 420, "url": "x"}]}`,
+		},
+		{
+			name: "extra keys allowed",
+			doc:  `{"name": "Cannon", "extra": 1, "size": {"width": 3, "height": 3}, "levels": []}`,
+		},
+		{
+			name: "numeric object in place of a number",
+			doc:  `{"name": "Mortar", "size": {"width": {"min": 3, "max": 4}, "height": 3}, "levels": []}`,
+		},
+		{
+			name: "missing required fields",
+			doc:  `{"size": {"width": 3}, "levels": []}`,
+			want: []string{"$.name", "$.size.height"},
+		},
+		{
+			name: "null counts as missing",
+			doc:  `{"name": null, "size": {"width": 3, "height": 3}, "levels": []}`,
+			want: []string{"$.name"},
+		},
+		{
+			name: "wrong kinds",
+			doc:  `{"name": 1, "size": "3x3", "levels": [{"level": "one", "hitpoints": 1, "url": "x"}]}`,
+			want: []string{"$.levels[0].level", "$.name", "$.size"}, // keys are checked in sorted order
+		},
+	}
+
+	for _, tt := range tests {
+		doc, err := decodeDocument([]byte(tt.doc))
+		if err != nil {
+			t.Fatalf("%s: decodeDocument error: %v", tt.name, err)
+		}
+		var got []string
+		s.check("$", doc, func(path, message string) {
+			got = append(got, path)
+		})
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: reported %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package data
+
+// SiegeMachine is the typed representation of a single siege machine data
+// file (e.g. data/home_village/siege_machines/wall_wrecker.json).
+type SiegeMachine struct {
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	HousingSpace   int          `json:"housingSpace"`
+	MovementSpeed  float64      `json:"movementSpeed"`
+	AttackSpeed    float64      `json:"attackSpeed,omitempty"`
+	Range          *Range       `json:"range,omitempty"`
+	FavoriteTarget string       `json:"favoriteTarget"`
+	TargetTypes    []string     `json:"targetTypes"`
+	Levels         []TroopLevel `json:"levels"`
+	Notes          string       `json:"notes,omitempty"`
+	Source         string       `json:"source"`
+	SourceURL      string       `json:"source_url"`
+	SourceLicense  string       `json:"source_license,omitempty"`
+
+	// UnlockedBy is the Workshop level that unlocks the siege machine. It is
+	// resolved from the building data, not read from the siege machine file.
+	UnlockedBy *Unlock `json:"unlockedBy,omitempty"`
+
+	// Extra holds siege-machine-specific top-level fields such as
+	// "deathDamage".
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a siege machine, keeping unmodeled fields in Extra.
+func (s *SiegeMachine) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, s, &s.Extra)
+}
+
+// MarshalJSON encodes a siege machine, including any fields kept in Extra.
+func (s SiegeMachine) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(s, s.Extra)
+}
@@ -0,0 +1,79 @@
+package data
+
+// SortFields are the fields list endpoints can be sorted by, besides "name".
+// Each is read from an item's level table (or its first mode's):
+//   - hitpoints: the highest hitpoints of any level
+//   - townHallRequired: the lowest Town Hall level required by any level
+//   - cost: the total amount of every level's cost, whatever its currency;
+//     alternatives are not counted
+//   - cost.<currency>, e.g. cost.gold: the total amount of every level that
+//     can be paid in that currency, as its cost or one of its alternatives
+var SortFields = append([]string{"hitpoints", "townHallRequired", "cost"}, costSortFields()...)
+
+// costSortFields returns the cost sort field of each of Currencies.
+func costSortFields() []string {
+	fields := make([]string, len(Currencies))
+	for i, c := range Currencies {
+		fields[i] = "cost." + string(c)
+	}
+	return fields
+}
+
+// sortValues reads the value of each of SortFields an item has.
+func sortValues(obj map[string]interface{}) map[string]int64 {
+	levels, _ := levelTable(obj)
+	values := make(map[string]int64)
+	for _, level := range levels {
+		if hp, ok := intValue(level["hitpoints"]); ok {
+			if cur, seen := values["hitpoints"]; !seen || int64(hp) > cur {
+				values["hitpoints"] = int64(hp)
+			}
+		}
+		if cost, ok := level["cost"].(map[string]interface{}); ok {
+			if amount, ok := intValue(cost["amount"]); ok {
+				values["cost"] += int64(amount)
+			}
+			for c, amount := range costOptions(cost) {
+				values["cost."+string(c)] += amount
+			}
+		}
+		if th, ok := intValue(level["townHallRequired"]); ok {
+			if cur, seen := values["townHallRequired"]; !seen || int64(th) < cur {
+				values["townHallRequired"] = int64(th)
+			}
+		}
+	}
+	return values
+}
+
+// costOptions reads the amount of a raw cost and of each of its
+// alternatives by currency. If a currency appears more than once, the
+// first amount is kept.
+func costOptions(cost map[string]interface{}) map[Currency]int64 {
+	options := make(map[Currency]int64)
+	add := func(price map[string]interface{}) {
+		name, _ := price["currency"].(string)
+		c, err := ParseCurrency(name)
+		if amount, ok := intValue(price["amount"]); ok && err == nil {
+			if _, seen := options[c]; !seen {
+				options[c] = int64(amount)
+			}
+		}
+	}
+
+	add(cost)
+	alternatives, _ := cost["alternatives"].([]interface{})
+	for _, alt := range alternatives {
+		if price, ok := alt.(map[string]interface{}); ok {
+			add(price)
+		}
+	}
+	return options
+}
+
+// SortValue returns the value of one of SortFields for the item at a
+// sub-path, and false if the item does not have it.
+func (s *Store) SortValue(subPath, field string) (int64, bool) {
+	v, ok := s.current.Load().sortValues[subPath][field]
+	return v, ok
+}
@@ -0,0 +1,29 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortValues(t *testing.T) {
+	doc, err := decodeDocument([]byte(`{"levels": [
+		{"level": 1, "hitpoints": 100, "townHallRequired": 3, "cost": {"amount": 1000, "currency": "Gold"}},
+		{"level": 2, "hitpoints": 300, "townHallRequired": 5, "cost": {"amount": 5000, "currency": "Gold", "alternatives": [{"amount": 6000, "currency": "Elixir"}]}},
+		{"level": 3, "hitpoints": 200, "cost": {"amount": 40, "currency": "Dark Elixir"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("decodeDocument error: %v", err)
+	}
+
+	want := map[string]int64{
+		"hitpoints":        300,
+		"townHallRequired": 3,
+		"cost":             6040,
+		"cost.gold":        6000,
+		"cost.elixir":      6000,
+		"cost.dark_elixir": 40,
+	}
+	if got := sortValues(doc.(map[string]interface{})); !reflect.DeepEqual(got, want) {
+		t.Errorf("sortValues = %v, want %v", got, want)
+	}
+}
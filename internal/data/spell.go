@@ -0,0 +1,57 @@
+package data
+
+// Spell is the typed representation of a single spell data file
+// (e.g. data/home_village/spells/elixir/lightning_spell.json).
+type Spell struct {
+	Name          string       `json:"name"`
+	Type          string       `json:"type"`
+	Description   string       `json:"description"`
+	HousingSpace  int          `json:"housingSpace"`
+	Radius        *float64     `json:"radius,omitempty"`
+	Duration      *Duration    `json:"duration,omitempty"`
+	Levels        []SpellLevel `json:"levels"`
+	Notes         string       `json:"notes,omitempty"`
+	Source        string       `json:"source"`
+	SourceURL     string       `json:"source_url"`
+	SourceLicense string       `json:"source_license,omitempty"`
+
+	// UnlockedBy is the spell factory level that unlocks the spell. It is
+	// resolved from the building data, not read from the spell file.
+	UnlockedBy *Unlock `json:"unlockedBy,omitempty"`
+
+	// Extra holds spell-specific top-level fields such as "numberOfStrikes".
+	Extra Extra `json:"-"`
+}
+
+// SpellLevel holds the effect values and research cost for one spell level.
+type SpellLevel struct {
+	Level           int       `json:"level"`
+	ResearchCost    *Cost     `json:"researchCost,omitempty"`
+	ResearchTime    *Duration `json:"researchTime,omitempty"`
+	LaboratoryLevel int       `json:"laboratoryLevelRequired,omitempty"`
+	Notes           string    `json:"notes,omitempty"`
+
+	// Extra holds the effect values of the level, named after what the spell
+	// does, e.g. "totalDamage", "damageBoost" or "healingPerSecond".
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a spell, keeping unmodeled fields in Extra.
+func (s *Spell) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, s, &s.Extra)
+}
+
+// MarshalJSON encodes a spell, including any fields kept in Extra.
+func (s Spell) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(s, s.Extra)
+}
+
+// UnmarshalJSON decodes a spell level, keeping effect values in Extra.
+func (l *SpellLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a spell level, including effect values kept in Extra.
+func (l SpellLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
@@ -0,0 +1,475 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Store serves a fully parsed snapshot of the data tree from memory. It is
+// built by Load and is safe for concurrent use. Reload replaces the snapshot
+// atomically, so each lookup sees either the old or the new data, never a mix.
+//
+// Its listing and lookup methods mirror Loader's. Returned values are shared
+// between callers and must not be modified.
+type Store struct {
+	current atomic.Pointer[dataset]
+
+	mu       sync.Mutex // serializes Reload and guards onReload and pending
+	onReload []func(bases []string)
+	pending  map[string]bool // bases changed since the last successful Reload
+}
+
+// Source is where the data in a file was taken from, and under which license.
+type Source struct {
+	Name    string `json:"source"`
+	URL     string `json:"source_url"`
+	License string `json:"source_license,omitempty"`
+}
+
+// dataset is an immutable snapshot of the data tree.
+type dataset struct {
+	version string
+
+	categories map[string][]CategoryInfo // by directory
+	items      map[string][]ItemSummary  // by directory
+	raw        map[string]json.RawMessage
+	sources    map[string]Source           // by item path, for files naming one
+	sortValues map[string]map[string]int64 // by item path, then sort field
+	entities   map[string]interface{}      // typed model by item path
+	bases      []string
+	buildings  map[string][]BuildingRef // by base
+	graphs     map[string]*Graph        // by base
+	counts     map[string]int           // entities by kind, across bases
+	modTimes   map[string]time.Time     // by item path and directory
+}
+
+// Load validates the data in fsys and reads all of it into a Store.
+// If any file is invalid, no Store is returned and the violations are.
+func Load(fsys fs.FS) (*Store, []Violation, error) {
+	d, violations, err := loadDataset(fsys)
+	if d == nil {
+		return nil, violations, err
+	}
+	s := &Store{}
+	s.current.Store(d)
+	return s, nil, nil
+}
+
+// Reload validates the data in fsys again and, if every file passes, swaps
+// it in for the current data and runs the OnReload hooks with the bases of
+// the changed files (paths relative to the data root). If any file is
+// invalid, the current data is kept and the violations are returned; the
+// changed bases are then passed to the hooks of the next successful Reload.
+func (s *Store) Reload(fsys fs.FS, changed []string) ([]Violation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == nil {
+		s.pending = make(map[string]bool)
+	}
+	for _, path := range changed {
+		base, _, _ := strings.Cut(path, "/")
+		s.pending[base] = true
+	}
+
+	d, violations, err := loadDataset(fsys)
+	if d == nil {
+		return violations, err
+	}
+	s.current.Store(d)
+
+	bases := make([]string, 0, len(s.pending))
+	for base := range s.pending {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+	clear(s.pending)
+	for _, fn := range s.onReload {
+		fn(bases)
+	}
+	return nil, nil
+}
+
+// OnReload registers fn to run after each successful Reload, e.g. to drop
+// values derived from the previous data.
+func (s *Store) OnReload(fn func(bases []string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReload = append(s.onReload, fn)
+}
+
+// loadDataset validates and reads the data in fsys. It returns a nil
+// dataset when the data cannot be read or has violations.
+func loadDataset(fsys fs.FS) (*dataset, []Violation, error) {
+	l := NewLoader(fsys)
+	violations, err := l.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(violations) > 0 {
+		return nil, violations, nil
+	}
+
+	ds := &dataset{
+		categories: make(map[string][]CategoryInfo),
+		items:      make(map[string][]ItemSummary),
+		raw:        make(map[string]json.RawMessage),
+		sources:    make(map[string]Source),
+		sortValues: make(map[string]map[string]int64),
+		entities:   make(map[string]interface{}),
+		buildings:  make(map[string][]BuildingRef),
+		graphs:     make(map[string]*Graph),
+		counts:     make(map[string]int),
+		modTimes:   make(map[string]time.Time),
+	}
+	if ds.bases, err = l.ListBases(); err != nil {
+		return nil, nil, err
+	}
+
+	err = fs.WalkDir(fsys, ".", func(dir string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || dir == "." {
+			return err
+		}
+		return ds.loadDir(l, dir)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load data: %w", err)
+	}
+
+	for _, base := range ds.bases {
+		if err := ds.link(l, base); err != nil {
+			return nil, nil, err
+		}
+	}
+	ds.version = ds.hash()
+	return ds, nil, nil
+}
+
+// hash identifies the content of the dataset: a hash of every data file and
+// its path, so it changes whenever any file does.
+func (ds *dataset) hash() string {
+	paths := make([]string, 0, len(ds.raw))
+	for path := range ds.raw {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		h.Write(ds.raw[path])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// loadDir records the listing of a directory and reads its data files.
+func (ds *dataset) loadDir(l *Loader, dir string) error {
+	categories, err := l.ListCategories(dir)
+	if err != nil {
+		return err
+	}
+	items, err := l.ListItems(dir)
+	if err != nil {
+		return err
+	}
+	ds.categories[dir] = categories
+	ds.items[dir] = items
+
+	// A directory's own time changes when files are added or removed.
+	modTime, err := l.ModTime(dir)
+	if err != nil {
+		return err
+	}
+	ds.touch(dir, modTime)
+
+	for _, it := range items {
+		raw, err := l.GetItem(it.Path)
+		if err != nil {
+			return err
+		}
+		ds.raw[it.Path] = raw
+		if doc, err := decodeDocument(raw); err == nil {
+			obj, _ := doc.(map[string]interface{})
+			if name, _ := obj["source"].(string); name != "" {
+				url, _ := obj["source_url"].(string)
+				license, _ := obj["source_license"].(string)
+				ds.sources[it.Path] = Source{Name: name, URL: url, License: license}
+			}
+			ds.sortValues[it.Path] = sortValues(obj)
+		}
+		if modTime, err = l.ModTime(it.Path); err != nil {
+			return err
+		}
+		ds.touch(it.Path, modTime)
+
+		for _, m := range typedModels {
+			if strings.Contains(it.Path+".json", m.dir) {
+				v := m.model()
+				if err := json.Unmarshal(raw, v); err != nil {
+					return fmt.Errorf("invalid data in %s: %w", it.Path, err)
+				}
+				ds.entities[it.Path] = v
+				ds.counts[strings.Trim(m.dir, "/")]++
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// touch records t as the modification time of path and of every directory
+// above it, keeping the latest time seen for each.
+func (ds *dataset) touch(path string, t time.Time) {
+	for {
+		if t.After(ds.modTimes[path]) {
+			ds.modTimes[path] = t
+		}
+		i := strings.LastIndex(path, "/")
+		if i < 0 {
+			return
+		}
+		path = path[:i]
+	}
+}
+
+// link resolves the references between the entities of a base: the units
+// each building unlocks, the building level unlocking each unit, and the
+// relations graph.
+func (ds *dataset) link(l *Loader, base string) error {
+	for _, c := range ds.categories[base+"/buildings"] {
+		for _, it := range ds.items[c.Path] {
+			ds.buildings[base] = append(ds.buildings[base], BuildingRef{
+				Category: c.Name,
+				Name:     it.Name,
+				Path:     it.Path,
+				Building: ds.entities[it.Path].(*Building),
+			})
+		}
+	}
+
+	// Validate already built the graph; the Loader hands back the same one.
+	g, err := l.Graph(base)
+	if err != nil {
+		return err
+	}
+	ds.graphs[base] = g
+
+	units := g.Units()
+	unlocks := NewUnlockIndex(ds.buildings[base])
+	for path, v := range ds.entities {
+		if !strings.HasPrefix(path, base+"/") {
+			continue
+		}
+		switch e := v.(type) {
+		case *Building:
+			e.Unlocks = e.UnlockLinks(units)
+		case *Troop:
+			e.UnlockedBy, _ = unlocks.Lookup("troops", e.Name)
+		case *Spell:
+			e.UnlockedBy, _ = unlocks.Lookup("spells", e.Name)
+		case *Hero:
+			e.UnlockedBy, _ = unlocks.Lookup("heroes", e.Name)
+		case *Equipment:
+			e.UnlockedBy, _ = unlocks.Lookup("equipment", e.Name)
+		case *Pet:
+			e.UnlockedBy, _ = unlocks.Lookup("pets", e.Name)
+		case *SiegeMachine:
+			e.UnlockedBy, _ = unlocks.Lookup("siege_machines", e.Name)
+		}
+	}
+	return nil
+}
+
+// Counts returns the number of entities of each kind (e.g. "buildings")
+// across every base.
+func (s *Store) Counts() map[string]int {
+	return s.current.Load().counts
+}
+
+// Version identifies the current data: it changes whenever any data file
+// does, so clients can tell which snapshot a response was built from.
+func (s *Store) Version() string {
+	return s.current.Load().version
+}
+
+// Source returns the attribution of the data file at a sub-path, if the
+// file names one.
+func (s *Store) Source(subPath string) (Source, bool) {
+	src, ok := s.current.Load().sources[subPath]
+	return src, ok
+}
+
+// ModTime returns the latest modification time of the data file at a
+// sub-path, or of any file in the directory at a sub-path. It is the zero
+// time when unknown, e.g. for embedded data.
+func (s *Store) ModTime(subPath string) time.Time {
+	return s.current.Load().modTimes[subPath]
+}
+
+// ListBases returns the names of the base directories.
+func (s *Store) ListBases() ([]string, error) {
+	return s.current.Load().bases, nil
+}
+
+// ListCategories returns the subdirectories of a directory, with the count
+// of data files in each.
+func (s *Store) ListCategories(subPath string) ([]CategoryInfo, error) {
+	d := s.current.Load()
+	categories, ok := d.categories[subPath]
+	if !ok {
+		return nil, fmt.Errorf("directory not found: %s", subPath)
+	}
+	return categories, nil
+}
+
+// ListItems returns the data files within a directory.
+func (s *Store) ListItems(subPath string) ([]ItemSummary, error) {
+	d := s.current.Load()
+	items, ok := d.items[subPath]
+	if !ok {
+		return nil, fmt.Errorf("directory not found: %s", subPath)
+	}
+	return items, nil
+}
+
+// GetItem returns the raw JSON of the data file at a sub-path.
+func (s *Store) GetItem(subPath string) (json.RawMessage, error) {
+	d := s.current.Load()
+	raw, ok := d.raw[subPath]
+	if !ok {
+		return nil, fmt.Errorf("item not found: %s", subPath)
+	}
+	return raw, nil
+}
+
+// entity returns the typed model stored at a sub-path.
+func (s *Store) entity(subPath string) (interface{}, error) {
+	d := s.current.Load()
+	v, ok := d.entities[subPath]
+	if !ok {
+		return nil, fmt.Errorf("item not found: %s", subPath)
+	}
+	return v, nil
+}
+
+// GetBuilding returns the building at a sub-path, with its unlock links.
+func (s *Store) GetBuilding(subPath string) (*Building, error) {
+	v, err := s.entity(subPath)
+	if b, ok := v.(*Building); ok {
+		return b, nil
+	}
+	return nil, notKind(err, subPath, "building")
+}
+
+// GetTroop returns the troop at a sub-path, with the level that unlocks it.
+func (s *Store) GetTroop(subPath string) (*Troop, error) {
+	v, err := s.entity(subPath)
+	if t, ok := v.(*Troop); ok {
+		return t, nil
+	}
+	return nil, notKind(err, subPath, "troop")
+}
+
+// GetSpell returns the spell at a sub-path, with the level that unlocks it.
+func (s *Store) GetSpell(subPath string) (*Spell, error) {
+	v, err := s.entity(subPath)
+	if sp, ok := v.(*Spell); ok {
+		return sp, nil
+	}
+	return nil, notKind(err, subPath, "spell")
+}
+
+// GetHero returns the hero at a sub-path, with the level that unlocks it.
+func (s *Store) GetHero(subPath string) (*Hero, error) {
+	v, err := s.entity(subPath)
+	if h, ok := v.(*Hero); ok {
+		return h, nil
+	}
+	return nil, notKind(err, subPath, "hero")
+}
+
+// GetEquipment returns the equipment at a sub-path, with the level that
+// unlocks it.
+func (s *Store) GetEquipment(subPath string) (*Equipment, error) {
+	v, err := s.entity(subPath)
+	if e, ok := v.(*Equipment); ok {
+		return e, nil
+	}
+	return nil, notKind(err, subPath, "equipment")
+}
+
+// GetPet returns the pet at a sub-path, with the level that unlocks it.
+func (s *Store) GetPet(subPath string) (*Pet, error) {
+	v, err := s.entity(subPath)
+	if p, ok := v.(*Pet); ok {
+		return p, nil
+	}
+	return nil, notKind(err, subPath, "pet")
+}
+
+// GetSiegeMachine returns the siege machine at a sub-path, with the level
+// that unlocks it.
+func (s *Store) GetSiegeMachine(subPath string) (*SiegeMachine, error) {
+	v, err := s.entity(subPath)
+	if m, ok := v.(*SiegeMachine); ok {
+		return m, nil
+	}
+	return nil, notKind(err, subPath, "siege machine")
+}
+
+// GetDistrict returns the district at a sub-path.
+func (s *Store) GetDistrict(subPath string) (*District, error) {
+	v, err := s.entity(subPath)
+	if d, ok := v.(*District); ok {
+		return d, nil
+	}
+	return nil, notKind(err, subPath, "district")
+}
+
+// GetTownHall returns the Town Hall data of a base.
+func (s *Store) GetTownHall(base string) (*TownHall, error) {
+	subPath := base + "/town_hall/town_hall"
+	v, err := s.entity(subPath)
+	if t, ok := v.(*TownHall); ok {
+		return t, nil
+	}
+	return nil, notKind(err, subPath, "town hall")
+}
+
+// ListBuildings returns every building of a base, in directory order.
+func (s *Store) ListBuildings(base string) ([]BuildingRef, error) {
+	d := s.current.Load()
+	if _, ok := d.categories[base+"/buildings"]; !ok {
+		return nil, fmt.Errorf("directory not found: %s/buildings", base)
+	}
+	return d.buildings[base], nil
+}
+
+// Graph returns the relations graph of a base.
+func (s *Store) Graph(base string) (*Graph, error) {
+	d := s.current.Load()
+	g, ok := d.graphs[base]
+	if !ok {
+		return nil, fmt.Errorf("base not found: %s", base)
+	}
+	return g, nil
+}
+
+// notKind returns err when the item was not found, or reports that it is
+// not of the requested kind.
+func notKind(err error, subPath, what string) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%s is not a %s", subPath, what)
+}
@@ -0,0 +1,61 @@
+package data
+
+import (
+	"io/fs"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// testData copies the repository's data tree into memory.
+func testData(t *testing.T) fstest.MapFS {
+	t.Helper()
+	src := os.DirFS("../../data")
+	fsys := fstest.MapFS{}
+	err := fs.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := fs.ReadFile(src, path)
+		fsys[path] = &fstest.MapFile{Data: b}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("reading data: %v", err)
+	}
+	return fsys
+}
+
+func TestReloadKeepsChangedBasesUntilSuccess(t *testing.T) {
+	fsys := testData(t)
+	s, violations, err := Load(fsys)
+	if err != nil || len(violations) > 0 {
+		t.Fatalf("Load = %v, %v", violations, err)
+	}
+
+	var got [][]string
+	s.OnReload(func(bases []string) { got = append(got, bases) })
+
+	const bad = "builder_base/buildings/army/broken.json"
+	fsys[bad] = &fstest.MapFile{Data: []byte("{")}
+	if violations, err := s.Reload(fsys, []string{bad}); err == nil && len(violations) == 0 {
+		t.Fatal("Reload accepted invalid data")
+	}
+	if len(got) != 0 {
+		t.Fatalf("hooks ran after a rejected reload: %v", got)
+	}
+
+	delete(fsys, bad)
+	if violations, err := s.Reload(fsys, []string{"home_village/buildings/army/template.json"}); err != nil || len(violations) > 0 {
+		t.Fatalf("Reload = %v, %v", violations, err)
+	}
+	if violations, err := s.Reload(fsys, []string{"clan_capital/x.json"}); err != nil || len(violations) > 0 {
+		t.Fatalf("Reload = %v, %v", violations, err)
+	}
+
+	want := [][]string{{"builder_base", "home_village"}, {"clan_capital"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hooks ran with %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,260 @@
+package data
+
+import "fmt"
+
+// AvailableAt returns how many copies of the building can be placed at the
+// given Town Hall level. Levels past the end of the availability table use
+// its last entry.
+func (b *Building) AvailableAt(townHall int) int {
+	count := 0
+	for _, a := range b.Availability.TownHallLevels {
+		if a.TownHall > townHall {
+			break
+		}
+		count = a.NumberAvailable
+	}
+	return count
+}
+
+// MaxLevelAt returns the highest level that can be reached at the given
+// Town Hall level, or 0 if none can.
+func (b *Building) MaxLevelAt(townHall int) int {
+	max := 0
+	for _, l := range b.UpgradeLevels() {
+		if l.TownHallRequired <= townHall && l.Level > max {
+			max = l.Level
+		}
+	}
+	return max
+}
+
+// MaxSuperchargeAt returns the highest charge level that can be reached at the
+// given Town Hall level, or 0 if the building cannot be supercharged there.
+func (b *Building) MaxSuperchargeAt(townHall int) int {
+	if b.MaxLevelAt(townHall) < b.MaxLevel() {
+		return 0
+	}
+	max := 0
+	for _, c := range b.Supercharges {
+		if c.TownHallRequired <= townHall && c.ChargeLevel > max {
+			max = c.ChargeLevel
+		}
+	}
+	return max
+}
+
+// MaxTownHall returns the highest Town Hall level listed in any building's
+// availability table.
+func MaxTownHall(refs []BuildingRef) int {
+	max := 0
+	for _, r := range refs {
+		for _, a := range r.Building.Availability.TownHallLevels {
+			if a.TownHall > max {
+				max = a.TownHall
+			}
+		}
+	}
+	return max
+}
+
+// TownHall is the typed representation of the Town Hall data file
+// (data/home_village/town_hall/town_hall.json).
+type TownHall struct {
+	Name          string          `json:"name"`
+	Size          Size            `json:"size"`
+	Description   string          `json:"description"`
+	Levels        []TownHallLevel `json:"levels"`
+	Notes         string          `json:"notes,omitempty"`
+	Source        string          `json:"source"`
+	SourceURL     string          `json:"source_url"`
+	SourceLicense string          `json:"source_license,omitempty"`
+
+	// Extra holds Town Hall top-level fields not modeled here.
+	Extra Extra `json:"-"`
+}
+
+// TownHallLevel holds the Town Hall's own stats at one level.
+type TownHallLevel struct {
+	Level            int                `json:"level"`
+	Hitpoints        int                `json:"hitpoints"`
+	Cost             *Cost              `json:"cost,omitempty"`
+	BuildTime        *Duration          `json:"buildTime,omitempty"`
+	ExperienceGained int                `json:"experienceGained"`
+	StorageCapacity  map[Currency]int64 `json:"storageCapacity"`
+	Weapon           *TownHallWeapon    `json:"weapon,omitempty"`
+	Notes            string             `json:"notes,omitempty"`
+
+	// Extra holds level stats not modeled here.
+	Extra Extra `json:"-"`
+}
+
+// TownHallWeapon is the weapon built into the Town Hall from Town Hall 12
+// (e.g. Giga Tesla), with its own upgrade levels at each Town Hall level.
+type TownHallWeapon struct {
+	Name   string        `json:"name"`
+	Levels []WeaponLevel `json:"levels"`
+}
+
+// WeaponLevel holds the stats and upgrade cost for one level of a Town Hall
+// weapon.
+type WeaponLevel struct {
+	Level            int       `json:"level"`
+	Cost             *Cost     `json:"cost,omitempty"`
+	BuildTime        *Duration `json:"buildTime,omitempty"`
+	ExperienceGained int       `json:"experienceGained,omitempty"`
+	Notes            string    `json:"notes,omitempty"`
+
+	// Extra holds the damage stats of the level, such as "damagePerSecond".
+	Extra Extra `json:"-"`
+}
+
+// Level returns the stats for Town Hall level n, or nil if there are none.
+func (t *TownHall) Level(n int) *TownHallLevel {
+	for i := range t.Levels {
+		if t.Levels[i].Level == n {
+			return &t.Levels[i]
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the Town Hall, keeping unmodeled fields in Extra.
+func (t *TownHall) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, t, &t.Extra)
+}
+
+// MarshalJSON encodes the Town Hall, including any fields kept in Extra.
+func (t TownHall) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(t, t.Extra)
+}
+
+// UnmarshalJSON decodes a Town Hall level, keeping unmodeled stats in Extra.
+func (l *TownHallLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a Town Hall level, including any stats kept in Extra.
+func (l TownHallLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
+
+// UnmarshalJSON decodes a weapon level, keeping damage stats in Extra.
+func (l *WeaponLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a weapon level, including damage stats kept in Extra.
+func (l WeaponLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
+
+// TownHallView lists everything that can be built at one Town Hall level,
+// along with the Town Hall's own stats at that level when they are known.
+type TownHallView struct {
+	TownHall  int                `json:"townHall"`
+	Stats     *TownHallLevel     `json:"stats,omitempty"`
+	Buildings []TownHallBuilding `json:"buildings"`
+}
+
+// TownHallBuilding is a building's availability at a given Town Hall level.
+type TownHallBuilding struct {
+	Name           string `json:"name"`
+	Category       string `json:"category"`
+	Path           string `json:"path"` // API path of the building
+	Count          int    `json:"count"`
+	MaxLevel       int    `json:"maxLevel"`
+	MaxSupercharge int    `json:"maxSupercharge,omitempty"`
+}
+
+// NewTownHallView builds the view of every building available at townHall.
+// Buildings with no copies available at that level are left out.
+func NewTownHallView(refs []BuildingRef, townHall int) (*TownHallView, error) {
+	if max := MaxTownHall(refs); townHall < 1 || townHall > max {
+		return nil, fmt.Errorf("town hall level must be between 1 and %d", max)
+	}
+
+	view := &TownHallView{TownHall: townHall, Buildings: []TownHallBuilding{}}
+	for _, r := range refs {
+		count := r.Building.AvailableAt(townHall)
+		if count == 0 {
+			continue
+		}
+		view.Buildings = append(view.Buildings, TownHallBuilding{
+			Name:           r.Building.Name,
+			Category:       r.Category,
+			Path:           "/api/" + r.Path,
+			Count:          count,
+			MaxLevel:       r.Building.MaxLevelAt(townHall),
+			MaxSupercharge: r.Building.MaxSuperchargeAt(townHall),
+		})
+	}
+	return view, nil
+}
+
+// MaxOutReport is the total price of maxing every building at a Town Hall
+// level, either from scratch or from a maxed lower Town Hall level.
+type MaxOutReport struct {
+	TownHall     int                       `json:"townHall"`
+	From         int                       `json:"from,omitempty"`
+	Supercharges bool                      `json:"supercharges"`
+	Total        *UpgradeTotals            `json:"total"`
+	Categories   map[string]*UpgradeTotals `json:"categories"`
+}
+
+// NewMaxOutReport sums the upgrades needed to bring every copy of every
+// building to its max level at townHall.
+//
+// With from = 0 every copy is built from scratch. Otherwise the copies
+// available at from are assumed maxed for that level, so only their remaining
+// upgrades are counted, plus new copies built from scratch. Supercharges are
+// included when supercharges is true.
+func NewMaxOutReport(refs []BuildingRef, townHall, from int, supercharges bool) (*MaxOutReport, error) {
+	max := MaxTownHall(refs)
+	switch {
+	case townHall < 1 || townHall > max:
+		return nil, fmt.Errorf("town hall level must be between 1 and %d", max)
+	case from < 0 || from >= townHall:
+		return nil, fmt.Errorf("from must be between 0 and %d", townHall-1)
+	}
+
+	report := &MaxOutReport{
+		TownHall:     townHall,
+		From:         from,
+		Supercharges: supercharges,
+		Total:        NewUpgradeTotals(),
+		Categories:   make(map[string]*UpgradeTotals),
+	}
+
+	for _, r := range refs {
+		b := r.Building
+		count := b.AvailableAt(townHall)
+		toLevel := b.MaxLevelAt(townHall)
+		if count == 0 || toLevel == 0 {
+			continue
+		}
+		toCharge := 0
+		if supercharges {
+			toCharge = b.MaxSuperchargeAt(townHall)
+		}
+
+		existing, fromLevel, fromCharge := 0, 0, 0
+		if from > 0 {
+			existing = min(b.AvailableAt(from), count)
+			fromLevel = b.MaxLevelAt(from)
+			if supercharges {
+				fromCharge = b.MaxSuperchargeAt(from)
+			}
+		}
+
+		totals := NewUpgradeTotals()
+		b.addUpgrades(totals, fromLevel, toLevel, fromCharge, toCharge, existing)
+		b.addUpgrades(totals, 0, toLevel, 0, toCharge, count-existing)
+
+		if report.Categories[r.Category] == nil {
+			report.Categories[r.Category] = NewUpgradeTotals()
+		}
+		report.Categories[r.Category].Add(totals)
+		report.Total.Add(totals)
+	}
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package data
+
+// Troop is the typed representation of a single troop data file
+// (e.g. data/home_village/troops/elixir/barbarian.json).
+type Troop struct {
+	Name           string       `json:"name"`
+	Type           string       `json:"type"`
+	Description    string       `json:"description"`
+	BaseTroop      string       `json:"baseTroop,omitempty"`
+	MinBaseLevel   int          `json:"minimumBaseTroopLevel,omitempty"`
+	HousingSpace   int          `json:"housingSpace"`
+	MovementSpeed  float64      `json:"movementSpeed"`
+	AttackSpeed    float64      `json:"attackSpeed"`
+	Range          *Range       `json:"range,omitempty"`
+	FavoriteTarget string       `json:"favoriteTarget"`
+	TargetTypes    []string     `json:"targetTypes"`
+	DamageType     string       `json:"damageType,omitempty"`
+	Levels         []TroopLevel `json:"levels"`
+	Notes          string       `json:"notes,omitempty"`
+	Source         string       `json:"source"`
+	SourceURL      string       `json:"source_url"`
+	SourceLicense  string       `json:"source_license,omitempty"`
+
+	// UnlockedBy is the barracks level that unlocks the troop. It is
+	// resolved from the building data, not read from the troop file.
+	UnlockedBy *Unlock `json:"unlockedBy,omitempty"`
+
+	// Extra holds troop-specific top-level fields such as
+	// "deathDamage" or "jumpsOverWalls".
+	Extra Extra `json:"-"`
+}
+
+// TroopLevel holds the stats and research cost for one troop level.
+type TroopLevel struct {
+	Level           int       `json:"level"`
+	DamagePerSecond float64   `json:"damagePerSecond,omitempty"`
+	Hitpoints       int       `json:"hitpoints"`
+	ResearchCost    *Cost     `json:"researchCost,omitempty"`
+	ResearchTime    *Duration `json:"researchTime,omitempty"`
+	LaboratoryLevel int       `json:"laboratoryLevelRequired,omitempty"`
+	Notes           string    `json:"notes,omitempty"`
+
+	// Extra holds level stats specific to a troop, such as "damagePerShot".
+	Extra Extra `json:"-"`
+}
+
+// MaxLevel returns the highest level of the troop.
+func (t *Troop) MaxLevel() int {
+	return len(t.Levels)
+}
+
+// UnmarshalJSON decodes a troop, keeping unmodeled fields in Extra.
+func (t *Troop) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, t, &t.Extra)
+}
+
+// MarshalJSON encodes a troop, including any fields kept in Extra.
+func (t Troop) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(t, t.Extra)
+}
+
+// UnmarshalJSON decodes a troop level, keeping unmodeled stats in Extra.
+func (l *TroopLevel) UnmarshalJSON(raw []byte) error {
+	return unmarshalWithExtra(raw, l, &l.Extra)
+}
+
+// MarshalJSON encodes a troop level, including any stats kept in Extra.
+func (l TroopLevel) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(l, l.Extra)
+}
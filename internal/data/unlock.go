@@ -0,0 +1,148 @@
+package data
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// unlockKinds maps the level fields that name newly unlocked units (e.g.
+// "unlockedSpells" on the Spell Factory) to the kind of unit they unlock.
+var unlockKinds = map[string]string{
+	"unlockedUnit":         "troops",
+	"unlockedSpell":        "spells",
+	"unlockedSpells":       "spells",
+	"unlockedHero":         "heroes",
+	"unlockedEquipment":    "equipment",
+	"unlockedPet":          "pets",
+	"unlockedSiegeMachine": "siege_machines",
+}
+
+// Unlock identifies the building level that unlocks a unit.
+type Unlock struct {
+	Building string `json:"building"`
+	Level    int    `json:"level"`
+	Path     string `json:"path"`
+}
+
+// UnlockIndex finds the building level that unlocks a unit, keyed by unit
+// kind and normalized name.
+type UnlockIndex map[string]Unlock
+
+// NewUnlockIndex scans the level tables of refs for unlock fields. When a
+// unit is listed more than once, the lowest level wins.
+func NewUnlockIndex(refs []BuildingRef) UnlockIndex {
+	ix := make(UnlockIndex)
+	for _, ref := range refs {
+		for _, l := range ref.Building.UpgradeLevels() {
+			for field, raw := range l.Extra {
+				kind, ok := unlockKinds[field]
+				if !ok {
+					continue
+				}
+				for _, name := range UnlockedNames(raw) {
+					key := unlockKey(kind, name)
+					if _, seen := ix[key]; !seen {
+						ix[key] = Unlock{
+							Building: ref.Building.Name,
+							Level:    l.Level,
+							Path:     "/api/" + ref.Path,
+						}
+					}
+				}
+			}
+		}
+	}
+	return ix
+}
+
+// Lookup returns the building level that unlocks the named unit of a kind.
+func (ix UnlockIndex) Lookup(kind, name string) (*Unlock, bool) {
+	u, ok := ix[unlockKey(kind, name)]
+	if !ok {
+		return nil, false
+	}
+	return &u, true
+}
+
+// UnlockedNames parses the value of an unlock field. Data files write it as
+// a single name, a comma-separated list ("Vampstache, Metal Pants") or an
+// array of names; "-" means nothing is unlocked at that level.
+func UnlockedNames(raw json.RawMessage) []string {
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil
+		}
+		list = strings.Split(s, ",")
+	}
+
+	var names []string
+	for _, name := range list {
+		if name = strings.TrimSpace(name); name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// UnitLink points from a building level to a unit it unlocks.
+type UnitLink struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+
+	// Path is the API path of the unit, or empty when there is no data
+	// file for it yet.
+	Path string `json:"path,omitempty"`
+}
+
+// UnitIndex maps unit kind and normalized name to the unit's API path.
+// It is built from a base's Graph (see Graph.Units).
+type UnitIndex map[string]string
+
+// UnlockLinks lists the units unlocked by each level of the building, in
+// level order, resolving them to API paths through units.
+func (b *Building) UnlockLinks(units UnitIndex) []UnitLink {
+	var links []UnitLink
+	for _, l := range b.UpgradeLevels() {
+		fields := make([]string, 0, len(l.Extra))
+		for field := range l.Extra {
+			if _, ok := unlockKinds[field]; ok {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			kind := unlockKinds[field]
+			for _, name := range UnlockedNames(l.Extra[field]) {
+				links = append(links, UnitLink{
+					Kind:  kind,
+					Name:  name,
+					Level: l.Level,
+					Path:  units[unlockKey(kind, name)],
+				})
+			}
+		}
+	}
+	return links
+}
+
+func unlockKey(kind, name string) string {
+	return kind + ":" + NormalizeName(name)
+}
+
+// NormalizeName lower-cases a display name and drops everything but letters
+// and digits, so "X-Bow", "x_bow" and "XBow" compare equal.
+func NormalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package data
+
+import "fmt"
+
+// MaxUpgradeCount is the most copies a single upgrade summary may cover. It
+// is far above any building's availability and keeps totals from overflowing.
+const MaxUpgradeCount = 1000
+
+// UpgradeTotals accumulates the price of a set of upgrades.
+//
+// Costs are summed by their primary currency; alternative prices are ignored.
+// Build time is the total builder time, not wall-clock time with several builders.
+type UpgradeTotals struct {
+	Cost       map[Currency]int64 `json:"cost"`
+	BuildTime  Duration           `json:"buildTime"`
+	Experience int                `json:"experience"`
+	Steps      int                `json:"steps"`
+}
+
+// NewUpgradeTotals returns empty totals.
+func NewUpgradeTotals() *UpgradeTotals {
+	return &UpgradeTotals{
+		Cost:      make(map[Currency]int64),
+		BuildTime: DurationFromSeconds(0),
+	}
+}
+
+// Add merges other into t.
+func (t *UpgradeTotals) Add(other *UpgradeTotals) {
+	for c, amount := range other.Cost {
+		t.Cost[c] += amount
+	}
+	t.BuildTime = DurationFromSeconds(t.BuildTime.Seconds + other.BuildTime.Seconds)
+	t.Experience += other.Experience
+	t.Steps += other.Steps
+}
+
+// addStep records one upgrade step performed count times.
+func (t *UpgradeTotals) addStep(cost Cost, buildTime *Duration, xp, count int) {
+	if cost.Amount > 0 {
+		t.Cost[cost.Currency] += cost.Amount * int64(count)
+	}
+	if buildTime != nil && buildTime.Valid {
+		t.BuildTime = DurationFromSeconds(t.BuildTime.Seconds + buildTime.Seconds*int64(count))
+	}
+	t.Experience += xp * count
+	t.Steps += count
+}
+
+// UpgradeSummary is the total price of taking one or more copies of a
+// building from one level to another.
+type UpgradeSummary struct {
+	Name         string `json:"name"`
+	From         int    `json:"from"`
+	To           int    `json:"to"`
+	Supercharges int    `json:"supercharges"`
+	Count        int    `json:"count"`
+	UpgradeTotals
+}
+
+// UpgradeLevels returns the level table used for upgrades. Buildings whose
+// stats are listed per attack mode (e.g. Inferno Tower) share one upgrade
+// path, so the first mode's levels are used when there is no top-level table.
+func (b *Building) UpgradeLevels() []Level {
+	if len(b.Levels) > 0 || len(b.Modes) == 0 {
+		return b.Levels
+	}
+	return b.Modes[0].Levels
+}
+
+// MaxLevel returns the highest regular level of the building.
+func (b *Building) MaxLevel() int {
+	return len(b.UpgradeLevels())
+}
+
+// Upgrade sums the cost, build time and experience of upgrading count copies
+// of the building from level from (0 meaning not yet built) to level to,
+// followed by the first supercharges charge levels.
+func (b *Building) Upgrade(from, to, supercharges, count int) (*UpgradeSummary, error) {
+	maxLevel := b.MaxLevel()
+
+	switch {
+	case maxLevel == 0:
+		return nil, fmt.Errorf("%s has no upgrade levels", b.Name)
+	case from < 0 || from > maxLevel:
+		return nil, fmt.Errorf("from must be between 0 and %d", maxLevel)
+	case to < 1 || to > maxLevel:
+		return nil, fmt.Errorf("to must be between 1 and %d", maxLevel)
+	case from > to:
+		return nil, fmt.Errorf("from (%d) must not be greater than to (%d)", from, to)
+	case supercharges < 0 || supercharges > len(b.Supercharges):
+		return nil, fmt.Errorf("supercharges must be between 0 and %d", len(b.Supercharges))
+	case supercharges > 0 && to != maxLevel:
+		return nil, fmt.Errorf("supercharges require upgrading to max level %d", maxLevel)
+	case count < 1 || count > MaxUpgradeCount:
+		return nil, fmt.Errorf("count must be between 1 and %d", MaxUpgradeCount)
+	}
+
+	s := &UpgradeSummary{
+		Name:          b.Name,
+		From:          from,
+		To:            to,
+		Supercharges:  supercharges,
+		Count:         count,
+		UpgradeTotals: *NewUpgradeTotals(),
+	}
+	b.addUpgrades(&s.UpgradeTotals, from, to, 0, supercharges, count)
+	return s, nil
+}
+
+// addUpgrades adds levels (fromLevel, toLevel] and charge levels
+// (fromCharge, toCharge] for count copies to t. Bounds must already be valid.
+func (b *Building) addUpgrades(t *UpgradeTotals, fromLevel, toLevel, fromCharge, toCharge, count int) {
+	if count <= 0 {
+		return
+	}
+	for _, l := range b.UpgradeLevels()[fromLevel:toLevel] {
+		t.addStep(l.Cost, l.BuildTime, l.ExperienceGained, count)
+	}
+	if fromCharge < toCharge {
+		for _, c := range b.Supercharges[fromCharge:toCharge] {
+			t.addStep(c.Cost, c.BuildTime, c.ExperienceGained, count)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+// testBuilding returns a three-level building with one supercharge.
+func testBuilding() *Building {
+	hour := DurationFromSeconds(3600)
+	return &Building{
+		Name: "Cannon",
+		Levels: []Level{
+			{Level: 1, Cost: Cost{Amount: 100, Currency: Gold}, BuildTime: &hour, ExperienceGained: 1},
+			{Level: 2, Cost: Cost{Amount: 200, Currency: Gold}, BuildTime: &hour, ExperienceGained: 2},
+			{Level: 3, Cost: Cost{Amount: 300, Currency: Elixir}, BuildTime: &hour, ExperienceGained: 3},
+		},
+		Supercharges: []Supercharge{
+			{ChargeLevel: 1, Cost: Cost{Amount: 1000, Currency: Gold}, BuildTime: &hour, ExperienceGained: 10},
+		},
+	}
+}
+
+func TestUpgradeTotals(t *testing.T) {
+	s, err := testBuilding().Upgrade(1, 3, 1, 2)
+	if err != nil {
+		t.Fatalf("Upgrade error: %v", err)
+	}
+	if got := s.Cost[Gold]; got != 2*(200+1000) {
+		t.Errorf("gold = %d, want %d", got, 2*(200+1000))
+	}
+	if got := s.Cost[Elixir]; got != 2*300 {
+		t.Errorf("elixir = %d, want %d", got, 2*300)
+	}
+	if got := s.BuildTime.Seconds; got != 6*3600 {
+		t.Errorf("build time = %d, want %d", got, 6*3600)
+	}
+	if s.Experience != 2*(2+3+10) || s.Steps != 6 {
+		t.Errorf("experience, steps = %d, %d, want %d, 6", s.Experience, s.Steps, 2*(2+3+10))
+	}
+}
+
+func TestUpgradeBounds(t *testing.T) {
+	tests := []struct {
+		from, to, supercharges, count int
+		wantErr                       string
+	}{
+		{0, 3, 0, 1, ""},
+		{3, 3, 0, 1, ""},
+		{0, 3, 0, MaxUpgradeCount, ""},
+		{-1, 3, 0, 1, "from must be between 0 and 3"},
+		{4, 3, 0, 1, "from must be between 0 and 3"},
+		{0, 0, 0, 1, "to must be between 1 and 3"},
+		{0, 4, 0, 1, "to must be between 1 and 3"},
+		{3, 2, 0, 1, "must not be greater than"},
+		{0, 3, 2, 1, "supercharges must be between 0 and 1"},
+		{0, 2, 1, 1, "supercharges require upgrading to max level 3"},
+		{0, 3, 0, 0, "count must be between 1 and 1000"},
+		{0, 3, 0, MaxUpgradeCount + 1, "count must be between 1 and 1000"},
+	}
+
+	for _, tt := range tests {
+		_, err := testBuilding().Upgrade(tt.from, tt.to, tt.supercharges, tt.count)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Upgrade(%d, %d, %d, %d) error: %v", tt.from, tt.to, tt.supercharges, tt.count, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Upgrade(%d, %d, %d, %d) error = %v, want %q", tt.from, tt.to, tt.supercharges, tt.count, err, tt.wantErr)
+		}
+	}
+
+	if _, err := (&Building{Name: "Empty"}).Upgrade(0, 1, 0, 1); err == nil {
+		t.Error("Upgrade of a building without levels succeeded")
+	}
+}
@@ -0,0 +1,277 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Violation describes a single problem found in a data file.
+type Violation struct {
+	File    string `json:"file"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String formats the violation as "file: path: message".
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.File, v.Path, v.Message)
+}
+
+// Validate checks every data file against the template.json in its directory.
+// Directories without a template, or with an empty one, are skipped.
+//
+// Besides the template shape, it checks that durations and currencies parse, that level
+// numbers are contiguous starting at 1 and that availability lists Town Hall
+// levels without gaps. Once every file passes, references between files
+// (unlocked units and required building levels) must resolve, including
+// those to kinds with no data files unless AllowMissingKinds is set.
+// The returned error is only non-nil when the data directory cannot be read.
+func (l *Loader) Validate() ([]Violation, error) {
+	var violations []Violation
+
+	err := fs.WalkDir(l.fsys, ".", func(relDir string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		tmplRaw, err := fs.ReadFile(l.fsys, path.Join(relDir, "template.json"))
+		if err != nil {
+			return nil // no template in this directory
+		}
+
+		tmpl, err := parseTemplate(tmplRaw)
+		if err != nil {
+			violations = append(violations, Violation{
+				File:    relDir + "/template.json",
+				Path:    "$",
+				Message: "invalid template: " + err.Error(),
+			})
+			return nil
+		}
+		if tmpl == nil {
+			return nil
+		}
+
+		files, err := l.listJSONFiles(relDir)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			raw, err := fs.ReadFile(l.fsys, path.Join(relDir, f))
+			if err != nil {
+				return err
+			}
+			violations = append(violations, validateFile(relDir+"/"+f, raw, tmpl)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk data directory: %w", err)
+	}
+
+	// References between files are only resolvable once every file is valid.
+	if len(violations) == 0 {
+		bases, err := l.ListBases()
+		if err != nil {
+			return nil, err
+		}
+		for _, base := range bases {
+			g, err := l.Graph(base)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, g.Dangling()...)
+			if !l.AllowMissingKinds {
+				violations = append(violations, g.Unchecked()...)
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].File < violations[j].File
+	})
+	return violations, nil
+}
+
+// Unchecked returns a warning for each kind of each base that has no data
+// files, so references to it could not be checked by Validate. Validate only
+// passes such references when AllowMissingKinds is set.
+func (l *Loader) Unchecked() ([]Violation, error) {
+	bases, err := l.ListBases()
+	if err != nil {
+		return nil, err
+	}
+	var warnings []Violation
+	for _, base := range bases {
+		g, err := l.Graph(base)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, g.Unchecked()...)
+	}
+	return warnings, nil
+}
+
+// typedModels maps the kind directory of a data file to the typed model its
+// files must decode into.
+var typedModels = []struct {
+	dir   string
+	what  string
+	model func() interface{}
+}{
+	{"/buildings/", "a building", func() interface{} { return new(Building) }},
+	{"/troops/", "a troop", func() interface{} { return new(Troop) }},
+	{"/spells/", "a spell", func() interface{} { return new(Spell) }},
+	{"/heroes/", "a hero", func() interface{} { return new(Hero) }},
+	{"/equipment/", "equipment", func() interface{} { return new(Equipment) }},
+	{"/pets/", "a pet", func() interface{} { return new(Pet) }},
+	{"/siege_machines/", "a siege machine", func() interface{} { return new(SiegeMachine) }},
+	{"/districts/", "a district", func() interface{} { return new(District) }},
+	{"/town_hall/", "a town hall", func() interface{} { return new(TownHall) }},
+}
+
+// validateFile checks a single data file against its category template.
+func validateFile(file string, raw []byte, tmpl *schema) []Violation {
+	var violations []Violation
+	report := func(path, message string) {
+		violations = append(violations, Violation{File: file, Path: path, Message: message})
+	}
+
+	doc, err := decodeDocument(raw)
+	if err != nil {
+		report("$", "invalid JSON: "+err.Error())
+		return violations
+	}
+
+	tmpl.check("$", doc, report)
+	checkValues("$", doc, report)
+
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return violations
+	}
+	checkSequence(obj, "levels", "level", 1, "$", report)
+	checkSequence(obj, "supercharges", "chargeLevel", 1, "$", report)
+	if levels, ok := obj["levels"].([]interface{}); ok {
+		for i, l := range levels {
+			level, _ := l.(map[string]interface{})
+			if storage, ok := level["storageCapacity"].(map[string]interface{}); ok {
+				for k := range storage {
+					if _, err := ParseCurrency(k); err != nil {
+						report(fmt.Sprintf("$.levels[%d].storageCapacity.%s", i, k), err.Error())
+					}
+				}
+			}
+			if weapon, ok := level["weapon"].(map[string]interface{}); ok {
+				checkSequence(weapon, "levels", "level", 1, fmt.Sprintf("$.levels[%d].weapon", i), report)
+			}
+		}
+	}
+	if modes, ok := obj["modes"].([]interface{}); ok {
+		for i, m := range modes {
+			if mode, ok := m.(map[string]interface{}); ok {
+				checkSequence(mode, "levels", "level", 1, fmt.Sprintf("$.modes[%d]", i), report)
+			}
+		}
+	}
+	if avail, ok := obj["availability"].(map[string]interface{}); ok {
+		checkSequence(avail, "townHallLevels", "townHall", 0, "$.availability", report)
+		checkSequence(avail, "builderHallLevels", "builderHall", 0, "$.availability", report)
+		if districts, ok := avail["districts"].([]interface{}); ok {
+			for i, d := range districts {
+				if district, ok := d.(map[string]interface{}); ok {
+					checkSequence(district, "hallLevels", "hallLevel", 0, fmt.Sprintf("$.availability.districts[%d]", i), report)
+				}
+			}
+		}
+	}
+
+	// Only report typed-model errors when the shape is otherwise valid,
+	// since the decoder stops at the first mismatch.
+	if len(violations) == 0 {
+		for _, m := range typedModels {
+			if strings.Contains(file, m.dir) {
+				if err := json.Unmarshal(raw, m.model()); err != nil {
+					report("$", "does not decode as "+m.what+": "+err.Error())
+				}
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// checkValues reports string values that do not parse as the type implied by
+// their key: durations (e.g. "buildTime") and currencies.
+func checkValues(path string, v interface{}, report func(path, message string)) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if s, ok := val.(string); ok {
+				if err := checkValue(k, s); err != nil {
+					report(path+"."+k, err.Error())
+				}
+				continue
+			}
+			checkValues(path+"."+k, val, report)
+		}
+	case []interface{}:
+		for i, item := range t {
+			checkValues(fmt.Sprintf("%s[%d]", path, i), item, report)
+		}
+	}
+}
+
+// checkValue parses s according to the field it appears under.
+func checkValue(key, s string) error {
+	switch {
+	case IsDurationField(key):
+		_, err := ParseDuration(s)
+		return err
+	case key == "currency":
+		_, err := ParseCurrency(s)
+		return err
+	}
+	return nil
+}
+
+// checkSequence verifies that the numbers in obj[list][i][key] increase by
+// exactly one per entry, starting at start. A start of 0 accepts whatever
+// the first entry holds, so only gaps, duplicates and reordering are reported.
+func checkSequence(obj map[string]interface{}, list, key string, start int, path string, report func(path, message string)) {
+	items, ok := obj[list].([]interface{})
+	if !ok {
+		return
+	}
+	next := start
+	for i, it := range items {
+		item, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		n, ok := item[key].(json.Number)
+		if !ok {
+			continue // type problems are reported by the template check
+		}
+		got, err := n.Int64()
+		if err != nil {
+			report(fmt.Sprintf("%s.%s[%d].%s", path, list, i, key), "expected an integer, got "+n.String())
+			continue
+		}
+		if next == 0 {
+			next = int(got)
+		}
+		if int(got) != next {
+			report(fmt.Sprintf("%s.%s[%d].%s", path, list, i, key),
+				fmt.Sprintf("expected %d, got %d (%s numbers must be contiguous)", next, got, key))
+		}
+		next = int(got) + 1
+	}
+}
@@ -0,0 +1,33 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMissingKinds(t *testing.T) {
+	fsys := testData(t)
+	for path := range fsys {
+		if strings.HasPrefix(path, "home_village/spells/") && !strings.HasSuffix(path, "/template.json") {
+			delete(fsys, path)
+		}
+	}
+
+	violations, err := NewLoader(fsys).Validate()
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if len(violations) != 1 || violations[0].File != "home_village/spells" {
+		t.Errorf("Validate = %v, want one violation for the missing spells", violations)
+	}
+
+	l := NewLoader(fsys)
+	l.AllowMissingKinds = true
+	if violations, err := l.Validate(); err != nil || len(violations) != 0 {
+		t.Errorf("Validate with AllowMissingKinds = %v, %v, want no violations", violations, err)
+	}
+	warnings, err := l.Unchecked()
+	if err != nil || len(warnings) != 1 || warnings[0].File != "home_village/spells" {
+		t.Errorf("Unchecked = %v, %v, want one warning for the missing spells", warnings, err)
+	}
+}
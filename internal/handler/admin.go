@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/flapjacck/CoCDB/internal/cache"
+)
+
+// CacheStatser is implemented by every cache.Cache, whatever its value type.
+type CacheStatser interface {
+	Stats() cache.Stats
+}
+
+// AdminHandler serves operational endpoints about the running server.
+type AdminHandler struct {
+	caches map[string]CacheStatser
+}
+
+// NewAdminHandler creates an AdminHandler reporting on the named caches.
+func NewAdminHandler(caches map[string]CacheStatser) *AdminHandler {
+	return &AdminHandler{caches: caches}
+}
+
+// CacheStats handles GET /admin/cache
+// Returns the size, capacity and hit/miss/eviction counters of each cache.
+func (h *AdminHandler) CacheStats(w http.ResponseWriter, r *http.Request) {
+	stats := make(map[string]cache.Stats, len(h.caches))
+	for name, c := range h.caches {
+		stats[name] = c.Stats()
+	}
+	w.Header().Set("Cache-Control", "no-store") // counters change on every request
+	Success(w, stats, newMeta(r, nil))
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5"
+)
+
+// KnownBase is middleware that responds 404 when the {base} URL parameter
+// is not one of the bases served by the API.
+func KnownBase(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if base := chi.URLParam(r, "base"); !data.IsBase(base) {
+			NotFound(w, "unknown base: "+base)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,69 +1,78 @@
 package handler
 
 import (
-	"log/slog"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"github.com/flapjacck/CoCDB/internal/cache"
 	"github.com/flapjacck/CoCDB/internal/data"
 	"github.com/go-chi/chi/v5"
 )
 
 // BuildingsHandler serves building-related API endpoints.
 type BuildingsHandler struct {
-	loader *data.Loader
-	cache  *cache.Cache
+	store *data.Store
 }
 
-// NewBuildingsHandler creates a handler with the given data loader and cache.
-func NewBuildingsHandler(loader *data.Loader, c *cache.Cache) *BuildingsHandler {
-	return &BuildingsHandler{loader: loader, cache: c}
+// NewBuildingsHandler creates a handler serving buildings from the given store.
+func NewBuildingsHandler(store *data.Store) *BuildingsHandler {
+	return &BuildingsHandler{store: store}
 }
 
 // ListCategories handles GET /api/{base}/buildings
 // Returns all building categories (army, defensive, resource, traps) with item counts.
 func (h *BuildingsHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
 	base := chi.URLParam(r, "base")
-	cacheKey := "buildings:categories:" + base
 
-	if cached, ok := h.cache.Get(cacheKey); ok {
-		Success(w, cached, nil)
-		return
-	}
-
-	buildingsBase := base + "/buildings"
-	categories, err := h.loader.ListCategories(buildingsBase)
+	categories, err := h.store.ListCategories(base + "/buildings")
 	if err != nil {
-		slog.Error("failed to list building categories", "error", err, "base", base)
-		InternalError(w, "failed to load building categories")
+		NotFound(w, "no buildings in base: "+base)
 		return
 	}
-
-	h.cache.Set(cacheKey, categories)
-	Success(w, categories, nil)
+	setLastModified(w, h.store, base+"/buildings")
+	writeCategories(w, r, h.store, categories)
 }
 
 // ListByCategory handles GET /api/{base}/buildings/{category}
 // Returns all buildings within a specific category.
+//
+// Optional query parameters narrow the list using each building's data:
+// targets, damageType, minTownHall, maxTownHall, hasSupercharge and size (e.g. "3x3").
 func (h *BuildingsHandler) ListByCategory(w http.ResponseWriter, r *http.Request) {
 	base := chi.URLParam(r, "base")
 	category := chi.URLParam(r, "category")
-	cacheKey := "buildings:list:" + base + ":" + category
 
-	if cached, ok := h.cache.Get(cacheKey); ok {
-		Success(w, cached, nil)
+	filter, err := parseBuildingFilter(r)
+	if err != nil {
+		BadRequest(w, err.Error())
 		return
 	}
 
-	buildingsBase := base + "/buildings"
-	items, err := h.loader.ListItems(buildingsBase + "/" + category)
+	items, err := h.store.ListItems(base + "/buildings/" + category)
 	if err != nil {
 		NotFound(w, "building category not found: "+category)
 		return
 	}
+	setLastModified(w, h.store, base+"/buildings/"+category)
 
-	h.cache.Set(cacheKey, items)
-	Success(w, items, nil)
+	if filter.IsZero() {
+		writeList(w, r, h.store, items)
+		return
+	}
+
+	matched := []data.ItemSummary{}
+	for _, it := range items {
+		b, err := h.store.GetBuilding(it.Path)
+		if err != nil {
+			InternalError(w, "failed to load building: "+it.Name)
+			return
+		}
+		if filter.Match(b) {
+			matched = append(matched, it)
+		}
+	}
+	writeList(w, r, h.store, matched)
 }
 
 // GetBuilding handles GET /api/{base}/buildings/{category}/{name}
@@ -72,20 +81,100 @@ func (h *BuildingsHandler) GetBuilding(w http.ResponseWriter, r *http.Request) {
 	base := chi.URLParam(r, "base")
 	category := chi.URLParam(r, "category")
 	name := chi.URLParam(r, "name")
-	cacheKey := "buildings:item:" + base + ":" + category + ":" + name
 
-	if cached, ok := h.cache.Get(cacheKey); ok {
-		Success(w, cached, nil)
+	subPath := base + "/buildings/" + category + "/" + name
+	item, err := h.store.GetBuilding(subPath)
+	if err != nil {
+		NotFound(w, "building not found: "+name)
 		return
 	}
+	setLastModified(w, h.store, subPath)
 
-	buildingsBase := base + "/buildings"
-	item, err := h.loader.GetItem(buildingsBase + "/" + category + "/" + name)
+	writeItem(w, r, item, itemMeta(r, h.store, subPath))
+}
+
+// GetUpgrade handles GET /api/{base}/buildings/{category}/{name}/upgrade
+// Returns the total cost, build time and experience to upgrade count copies
+// of a building between two levels.
+//
+// Query parameters:
+//   - from: current level, 0 for not yet built (default 0)
+//   - to: target level (default max level)
+//   - supercharges: number of charge levels to add after max level (default 0)
+//   - count: number of copies (default 1, at most data.MaxUpgradeCount)
+func (h *BuildingsHandler) GetUpgrade(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	category := chi.URLParam(r, "category")
+	name := chi.URLParam(r, "name")
+
+	subPath := base + "/buildings/" + category + "/" + name
+	b, err := h.store.GetBuilding(subPath)
 	if err != nil {
 		NotFound(w, "building not found: "+name)
 		return
 	}
+	setLastModified(w, h.store, subPath)
 
-	h.cache.Set(cacheKey, item)
-	Success(w, item, nil)
+	from, err := queryInt(r, "from", 0)
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	to, err := queryInt(r, "to", b.MaxLevel())
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	supercharges, err := queryInt(r, "supercharges", 0)
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	count, err := queryInt(r, "count", 1)
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+
+	summary, err := b.Upgrade(from, to, supercharges, count)
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	writeItem(w, r, summary, itemMeta(r, h.store, subPath))
+}
+
+// parseBuildingFilter reads building filter options from the query string.
+func parseBuildingFilter(r *http.Request) (data.BuildingFilter, error) {
+	q := r.URL.Query()
+	f := data.BuildingFilter{
+		Target:     q.Get("targets"),
+		DamageType: q.Get("damageType"),
+	}
+
+	var err error
+	if f.MinTownHall, err = queryInt(r, "minTownHall", 0); err != nil {
+		return f, err
+	}
+	if f.MaxTownHall, err = queryInt(r, "maxTownHall", 0); err != nil {
+		return f, err
+	}
+
+	if v := q.Get("hasSupercharge"); v != "" {
+		has, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, fmt.Errorf("hasSupercharge must be true or false")
+		}
+		f.HasSupercharge = &has
+	}
+
+	if v := q.Get("size"); v != "" {
+		w, hgt, ok := strings.Cut(v, "x")
+		f.Width, _ = strconv.Atoi(w)
+		f.Height, _ = strconv.Atoi(hgt)
+		if !ok || f.Width < 1 || f.Height < 1 {
+			return f, fmt.Errorf("size must look like 3x3")
+		}
+	}
+	return f, nil
 }
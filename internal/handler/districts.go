@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5"
+)
+
+// DistrictsHandler serves Clan Capital districts and the buildings that can
+// be placed in each.
+type DistrictsHandler struct {
+	store *data.Store
+}
+
+// NewDistrictsHandler creates a handler serving districts from the given store.
+func NewDistrictsHandler(store *data.Store) *DistrictsHandler {
+	return &DistrictsHandler{store: store}
+}
+
+// List handles GET /api/{base}/districts
+// Returns every district in the base.
+func (h *DistrictsHandler) List(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+
+	items, err := h.store.ListItems(base + "/districts")
+	if err != nil {
+		NotFound(w, "no districts in base: "+base)
+		return
+	}
+	setLastModified(w, h.store, base+"/districts")
+	writeList(w, r, h.store, items)
+}
+
+// Get handles GET /api/{base}/districts/{district}
+// Returns full data for a district, including its hall levels.
+func (h *DistrictsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	name := chi.URLParam(r, "district")
+
+	subPath := base + "/districts/" + name
+	d, err := h.store.GetDistrict(subPath)
+	if err != nil {
+		NotFound(w, "district not found: "+name)
+		return
+	}
+	setLastModified(w, h.store, subPath)
+	writeItem(w, r, d, itemMeta(r, h.store, subPath))
+}
+
+// ListBuildings handles GET /api/{base}/districts/{district}/buildings
+// Returns every building that can be placed in the district.
+func (h *DistrictsHandler) ListBuildings(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	name := chi.URLParam(r, "district")
+
+	if _, err := h.store.GetDistrict(base + "/districts/" + name); err != nil {
+		NotFound(w, "district not found: "+name)
+		return
+	}
+
+	refs, _ := h.store.ListBuildings(base)
+	setLastModified(w, h.store, base)
+
+	items := []data.ItemSummary{}
+	for _, ref := range refs {
+		if ref.Building.InDistrict(name) {
+			items = append(items, data.ItemSummary{Name: ref.Name, Path: ref.Path})
+		}
+	}
+	writeList(w, r, h.store, items)
+}
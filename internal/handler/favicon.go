@@ -1,28 +1,29 @@
 package handler
 
 import (
+	"io/fs"
 	"net/http"
-	"os"
 )
 
-// FaviconHandler serves a favicon from a file path on disk.
+// FaviconHandler serves a favicon from a file system.
 // If no favicon file exists yet, it returns 204 No Content.
 type FaviconHandler struct {
-	filePath string
+	fsys fs.FS
+	name string
 }
 
-// NewFaviconHandler creates a handler that serves the favicon at the given path.
+// NewFaviconHandler creates a handler that serves the favicon at name in fsys.
 // Place your favicon.ico in the static/ directory to enable it.
-func NewFaviconHandler(filePath string) *FaviconHandler {
-	return &FaviconHandler{filePath: filePath}
+func NewFaviconHandler(fsys fs.FS, name string) *FaviconHandler {
+	return &FaviconHandler{fsys: fsys, name: name}
 }
 
 // ServeHTTP serves the favicon file or returns 204 if none is configured.
 // Route: GET /favicon.ico
 func (h *FaviconHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if _, err := os.Stat(h.filePath); os.IsNotExist(err) {
+	if _, err := fs.Stat(h.fsys, h.name); err != nil {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	http.ServeFile(w, r, h.filePath)
+	http.ServeFileFS(w, r, h.fsys, h.name)
 }
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5"
+)
+
+// HeroesHandler serves heroes, hero equipment and pets. Unlike troops and
+// spells these have no categories, so each kind is a single flat directory.
+type HeroesHandler struct {
+	store *data.Store
+}
+
+// NewHeroesHandler creates a handler serving heroes, equipment and pets from
+// the given store.
+func NewHeroesHandler(store *data.Store) *HeroesHandler {
+	return &HeroesHandler{store: store}
+}
+
+// ListHeroes handles GET /api/{base}/heroes
+func (h *HeroesHandler) ListHeroes(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "heroes")
+}
+
+// ListEquipment handles GET /api/{base}/equipment
+func (h *HeroesHandler) ListEquipment(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "equipment")
+}
+
+// ListPets handles GET /api/{base}/pets
+func (h *HeroesHandler) ListPets(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "pets")
+}
+
+// list returns every item of a kind in the requested base.
+func (h *HeroesHandler) list(w http.ResponseWriter, r *http.Request, kind string) {
+	base := chi.URLParam(r, "base")
+
+	items, err := h.store.ListItems(base + "/" + kind)
+	if err != nil {
+		NotFound(w, "no "+kind+" in base: "+base)
+		return
+	}
+	setLastModified(w, h.store, base+"/"+kind)
+	writeList(w, r, h.store, items)
+}
+
+// GetHero handles GET /api/{base}/heroes/{name}
+// Returns full data for a specific hero, including the Hero Hall level
+// that unlocks it.
+func (h *HeroesHandler) GetHero(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	name := chi.URLParam(r, "name")
+
+	subPath := base + "/heroes/" + name
+	hero, err := h.store.GetHero(subPath)
+	if err != nil {
+		NotFound(w, "hero not found: "+name)
+		return
+	}
+	setLastModified(w, h.store, subPath)
+
+	writeItem(w, r, hero, itemMeta(r, h.store, subPath))
+}
+
+// GetEquipment handles GET /api/{base}/equipment/{name}
+// Returns full data for a specific piece of hero equipment, including the
+// Blacksmith level that unlocks it.
+func (h *HeroesHandler) GetEquipment(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	name := chi.URLParam(r, "name")
+
+	subPath := base + "/equipment/" + name
+	eq, err := h.store.GetEquipment(subPath)
+	if err != nil {
+		NotFound(w, "equipment not found: "+name)
+		return
+	}
+	setLastModified(w, h.store, subPath)
+
+	writeItem(w, r, eq, itemMeta(r, h.store, subPath))
+}
+
+// GetPet handles GET /api/{base}/pets/{name}
+// Returns full data for a specific pet, including the Pet House level that
+// unlocks it.
+func (h *HeroesHandler) GetPet(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	name := chi.URLParam(r, "name")
+
+	subPath := base + "/pets/" + name
+	pet, err := h.store.GetPet(subPath)
+	if err != nil {
+		NotFound(w, "pet not found: "+name)
+		return
+	}
+	setLastModified(w, h.store, subPath)
+
+	writeItem(w, r, pet, itemMeta(r, h.store, subPath))
+}
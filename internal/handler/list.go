@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+)
+
+// listOptions are the sorting and paging options parsed from a list request.
+type listOptions struct {
+	sort   string  // "", "name" or one of the list's sort fields; "" keeps directory order
+	desc   bool    // reverse the sort field's order
+	limit  int     // items per page; 0 means every item
+	offset int     // items to skip
+	after  *cursor // resume after this item instead of skipping offset items
+}
+
+// cursor identifies the last item of a page by its position in the sort
+// order, so paging stays consistent even if items are added or removed.
+type cursor struct {
+	Value *int64 `json:"v,omitempty"` // the sort field's value, if the item has it
+	Name  string `json:"n"`
+	Path  string `json:"p"`
+}
+
+// listEntry is an item with the key it is sorted by.
+type listEntry[T any] struct {
+	cursor
+	item T
+}
+
+// parseListOptions reads the sorting and paging options from the query
+// string. Besides "name", the list can be sorted by fields; initial is the
+// sort used when none is given.
+func parseListOptions(r *http.Request, fields []string, initial string) (listOptions, error) {
+	q := r.URL.Query()
+	opts := listOptions{sort: initial}
+
+	if q.Has("sort") {
+		opts.sort = q.Get("sort")
+		if opts.sort != "name" && !slices.Contains(fields, opts.sort) {
+			return opts, fmt.Errorf("invalid sort %q (expected %s)", opts.sort, strings.Join(append([]string{"name"}, fields...), ", "))
+		}
+	}
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		opts.desc = true
+	default:
+		return opts, fmt.Errorf("order must be asc or desc")
+	}
+
+	var err error
+	if opts.limit, err = queryInt(r, "limit", 0); err != nil || opts.limit < 0 || q.Has("limit") && opts.limit == 0 {
+		return opts, fmt.Errorf("limit must be a positive integer")
+	}
+	if opts.offset, err = queryInt(r, "offset", 0); err != nil || opts.offset < 0 {
+		return opts, fmt.Errorf("offset must be a non-negative integer")
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		if q.Has("offset") {
+			return opts, fmt.Errorf("use either cursor or offset, not both")
+		}
+		raw, err := base64.RawURLEncoding.DecodeString(v)
+		opts.after = &cursor{}
+		if err != nil || json.Unmarshal(raw, opts.after) != nil || opts.after.Path == "" {
+			return opts, fmt.Errorf("invalid cursor")
+		}
+	}
+	return opts, nil
+}
+
+// compare orders two entries by the sort field, then by name and path.
+// Items without the sort field come last in either order.
+func (o listOptions) compare(a, b cursor) int {
+	var c int
+	switch {
+	case o.sort == "":
+		// Directory order, which lists files by name.
+		c = cmp.Compare(a.Path, b.Path)
+	case o.sort == "name":
+		c = cmp.Compare(a.Name, b.Name)
+	case (a.Value == nil) != (b.Value == nil):
+		if a.Value == nil {
+			return 1
+		}
+		return -1
+	case a.Value != nil:
+		c = cmp.Compare(*a.Value, *b.Value)
+	}
+	if o.desc {
+		c = -c
+	}
+	if c != 0 {
+		return c
+	}
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Path, b.Path))
+}
+
+// writeList sorts and pages items with the request's options and sends the
+// page, responding 400 if the options are invalid.
+//
+// Supported options:
+//   - sort=name|hitpoints|townHallRequired|cost|cost.<currency> and order=asc|desc
+//     (default: directory order, ascending)
+//   - limit=N: return at most N items (default: every item)
+//   - cursor=C: resume after the page that returned C as meta.next
+//   - offset=N: skip the first N items instead of using a cursor
+func writeList(w http.ResponseWriter, r *http.Request, store *data.Store, items []data.ItemSummary) {
+	opts, err := parseListOptions(r, data.SortFields, "")
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+
+	entries := make([]listEntry[data.ItemSummary], len(items))
+	for i, it := range items {
+		entries[i] = listEntry[data.ItemSummary]{cursor: cursor{Name: it.Name, Path: it.Path}, item: it}
+		if opts.sort != "" && opts.sort != "name" {
+			if v, ok := store.SortValue(it.Path, opts.sort); ok {
+				entries[i].Value = &v
+			}
+		}
+	}
+	writePage(w, r, store, opts, entries)
+}
+
+// writeCategories sorts and pages categories like writeList, except that
+// they can be sorted by name or count only.
+func writeCategories(w http.ResponseWriter, r *http.Request, store *data.Store, categories []data.CategoryInfo) {
+	opts, err := parseListOptions(r, []string{"count"}, "")
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+
+	entries := make([]listEntry[data.CategoryInfo], len(categories))
+	for i, c := range categories {
+		count := int64(c.Count)
+		entries[i] = listEntry[data.CategoryInfo]{cursor: cursor{Value: &count, Name: c.Name, Path: c.Path}, item: c}
+	}
+	writePage(w, r, store, opts, entries)
+}
+
+// writePage sorts entries with opts and sends the page they select. When
+// more entries follow, the next page's cursor is set in meta.next and a
+// Link header.
+func writePage[T any](w http.ResponseWriter, r *http.Request, store *data.Store, opts listOptions, entries []listEntry[T]) {
+	slices.SortStableFunc(entries, func(a, b listEntry[T]) int {
+		return opts.compare(a.cursor, b.cursor)
+	})
+
+	start := min(opts.offset, len(entries))
+	if opts.after != nil {
+		start, _ = slices.BinarySearchFunc(entries, *opts.after, func(e listEntry[T], c cursor) int {
+			if opts.compare(e.cursor, c) <= 0 {
+				return -1
+			}
+			return 1
+		})
+	}
+	end := len(entries)
+	if opts.limit > 0 {
+		end = min(start+opts.limit, end)
+	}
+
+	page := make([]T, 0, end-start)
+	for _, e := range entries[start:end] {
+		page = append(page, e.item)
+	}
+
+	total := len(entries)
+	meta := listMeta(r, store, len(page))
+	meta.Total = &total
+	if end < len(entries) {
+		raw, _ := json.Marshal(entries[end-1].cursor)
+		meta.Next = base64.RawURLEncoding.EncodeToString(raw)
+
+		q := r.URL.Query()
+		q.Del("offset")
+		q.Set("cursor", meta.Next)
+		w.Header().Set("Link", "<"+r.URL.Path+"?"+q.Encode()+`>; rel="next"`)
+	}
+	Success(w, page, meta)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+)
+
+// testStore loads the repository's data tree.
+func testStore(t *testing.T) *data.Store {
+	t.Helper()
+	store, violations, err := data.Load(os.DirFS("../../data"))
+	if err != nil || len(violations) > 0 {
+		t.Fatalf("Load = %v, %v", violations, err)
+	}
+	return store
+}
+
+// listPage is the decoded response of a list request.
+type listPage[T any] struct {
+	Data []T  `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+// getPage calls write with a request for query and decodes the response.
+func getPage[T any](t *testing.T, query string, write func(http.ResponseWriter, *http.Request)) (listPage[T], int) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	write(rec, httptest.NewRequest(http.MethodGet, "/list?"+query, nil))
+
+	var page listPage[T]
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decoding %s: %v", query, err)
+		}
+		if page.Meta.Next != "" && rec.Header().Get("Link") == "" {
+			t.Errorf("%s: meta.next set without a Link header", query)
+		}
+	}
+	return page, rec.Code
+}
+
+func TestWriteListCursor(t *testing.T) {
+	store := testStore(t)
+	var items []data.ItemSummary
+	for i := range 7 {
+		items = append(items, data.ItemSummary{Name: fmt.Sprintf("Item %d", 6-i), Path: fmt.Sprintf("test/item_%d", i)})
+	}
+	write := func(w http.ResponseWriter, r *http.Request) { writeList(w, r, store, items) }
+
+	for _, order := range []string{"asc", "desc"} {
+		var names []string
+		query := "sort=name&limit=3&order=" + order
+		for pages := 0; ; pages++ {
+			if pages > len(items) {
+				t.Fatalf("%s: paging did not end", order)
+			}
+			page, code := getPage[data.ItemSummary](t, query, write)
+			if code != http.StatusOK {
+				t.Fatalf("%s: status %d", query, code)
+			}
+			if *page.Meta.Total != len(items) || *page.Meta.Count != len(page.Data) {
+				t.Errorf("%s: total, count = %d, %d", query, *page.Meta.Total, *page.Meta.Count)
+			}
+			for _, it := range page.Data {
+				names = append(names, it.Name)
+			}
+			if page.Meta.Next == "" {
+				break
+			}
+			query = "sort=name&limit=3&order=" + order + "&cursor=" + url.QueryEscape(page.Meta.Next)
+		}
+
+		want := []string{"Item 0", "Item 1", "Item 2", "Item 3", "Item 4", "Item 5", "Item 6"}
+		if order == "desc" {
+			want = []string{"Item 6", "Item 5", "Item 4", "Item 3", "Item 2", "Item 1", "Item 0"}
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("%s: paged through %v, want %v", order, names, want)
+		}
+	}
+
+	// Offsets page through the same order.
+	page, _ := getPage[data.ItemSummary](t, "sort=name&limit=2&offset=5", write)
+	if len(page.Data) != 2 || page.Data[0].Name != "Item 5" || page.Meta.Next != "" {
+		t.Errorf("offset page = %+v, next %q", page.Data, page.Meta.Next)
+	}
+
+	if _, code := getPage[data.ItemSummary](t, "sort=cost&order=desc", write); code != http.StatusOK {
+		t.Errorf("sort=cost: status %d, want 200", code)
+	}
+	for _, query := range []string{"sort=cost.bitcoin", "sort=count", "order=up", "limit=0", "offset=-1", "cursor=x", "cursor=e30&offset=1"} {
+		if _, code := getPage[data.ItemSummary](t, query, write); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, code)
+		}
+	}
+}
+
+func TestWriteCategories(t *testing.T) {
+	store := testStore(t)
+	categories := []data.CategoryInfo{
+		{Name: "army", Count: 2, Path: "test/army"},
+		{Name: "defensive", Count: 5, Path: "test/defensive"},
+		{Name: "traps", Count: 2, Path: "test/traps"},
+	}
+	write := func(w http.ResponseWriter, r *http.Request) { writeCategories(w, r, store, categories) }
+
+	page, code := getPage[data.CategoryInfo](t, "sort=count&order=desc&limit=2", write)
+	if code != http.StatusOK || len(page.Data) != 2 || page.Data[0].Name != "defensive" || page.Data[1].Name != "army" {
+		t.Fatalf("first page = %d %+v", code, page.Data)
+	}
+	page, _ = getPage[data.CategoryInfo](t, "sort=count&order=desc&limit=2&cursor="+page.Meta.Next, write)
+	if len(page.Data) != 1 || page.Data[0].Name != "traps" || page.Meta.Next != "" {
+		t.Errorf("second page = %+v, next %q", page.Data, page.Meta.Next)
+	}
+
+	if _, code := getPage[data.CategoryInfo](t, "sort=hitpoints", write); code != http.StatusBadRequest {
+		t.Errorf("sort=hitpoints: status %d, want 400", code)
+	}
+}
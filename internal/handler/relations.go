@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5"
+)
+
+// RelationsHandler serves the resolved relationships of any entity.
+type RelationsHandler struct {
+	store *data.Store
+}
+
+// NewRelationsHandler creates a handler serving relations from the given store.
+func NewRelationsHandler(store *data.Store) *RelationsHandler {
+	return &RelationsHandler{store: store}
+}
+
+// Get handles GET /api/{base}/{kind}/{category}/{name}/relations, and
+// GET /api/{base}/{kind}/{name}/relations for kinds without categories.
+// Returns what the entity unlocks, what unlocks it and what it requires.
+func (h *RelationsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+
+	g, err := h.store.Graph(base)
+	if err != nil {
+		NotFound(w, "no data in base: "+base)
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/relations")
+	rel, ok := g.Lookup(path)
+	if !ok {
+		NotFound(w, "item not found: "+strings.TrimPrefix(path, "/api/"+base+"/"))
+		return
+	}
+	setLastModified(w, h.store, base)
+	Success(w, rel, newMeta(r, h.store))
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+)
+
+// Duration formats accepted by the ?durations= query parameter on item endpoints.
+const (
+	durationsSeconds = "seconds" // integer seconds
+	durationsISO8601 = "iso8601" // e.g. "P1DT12H"
+	durationsHuman   = "human"   // canonical short form, e.g. "1d 12h"
+)
+
+// writeItem renders a single item with the request's options and sends it
+// with meta, responding 400 if the options are invalid.
+func writeItem(w http.ResponseWriter, r *http.Request, item interface{}, meta *Meta) {
+	out, err := renderItem(r, item)
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	Success(w, out, meta)
+}
+
+// setLastModified sets the Last-Modified header to the modification time of
+// the data at subPath, when the store knows it.
+func setLastModified(w http.ResponseWriter, store *data.Store, subPath string) {
+	if t := store.ModTime(subPath); !t.IsZero() {
+		w.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+	}
+}
+
+// renderOptions are the presentation options parsed from an item request.
+type renderOptions struct {
+	durations          string     // "", or one of the durations* formats
+	fields             [][]string // dotted field paths split on "."
+	minLevel, maxLevel int        // level range to keep; 0 means no slicing
+}
+
+// parseRenderOptions reads the presentation options from the query string.
+func parseRenderOptions(r *http.Request) (renderOptions, error) {
+	q := r.URL.Query()
+	var opts renderOptions
+
+	switch opts.durations = q.Get("durations"); opts.durations {
+	case "", durationsSeconds, durationsISO8601, durationsHuman:
+	default:
+		return opts, fmt.Errorf("invalid durations format %q (expected seconds, iso8601 or human)", opts.durations)
+	}
+
+	if v := q.Get("fields"); v != "" {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				opts.fields = append(opts.fields, strings.Split(f, "."))
+			}
+		}
+	}
+
+	level, levels := q.Get("level"), q.Get("levels")
+	switch {
+	case level != "" && levels != "":
+		return opts, fmt.Errorf("use either level or levels, not both")
+	case level != "":
+		n, err := strconv.Atoi(level)
+		if err != nil || n < 1 {
+			return opts, fmt.Errorf("level must be a positive integer")
+		}
+		opts.minLevel, opts.maxLevel = n, n
+	case levels != "":
+		lo, hi, ok := strings.Cut(levels, "-")
+		var err1, err2 error
+		opts.minLevel, err1 = strconv.Atoi(lo)
+		opts.maxLevel, err2 = strconv.Atoi(hi)
+		if !ok || err1 != nil || err2 != nil || opts.minLevel < 1 || opts.minLevel > opts.maxLevel {
+			return opts, fmt.Errorf("levels must be a range like 10-14")
+		}
+	}
+	return opts, nil
+}
+
+// renderItem applies the presentation options in the request's query string
+// to a single item before it is encoded. Items are returned unchanged when
+// no options are given.
+//
+// Supported options, applied in this order:
+//   - level=N or levels=A-B: keep only those entries of "levels"
+//     (including per-mode level tables).
+//   - fields=name,levels.hitpoints: keep only the listed fields. Arrays are
+//     traversed transparently, and "level"/"chargeLevel" are always kept in
+//     array entries so projected rows stay identifiable.
+//   - durations=seconds|iso8601|human: replace each duration's
+//     {"raw", "seconds"} object with a single value in that format.
+func renderItem(r *http.Request, item interface{}) (interface{}, error) {
+	opts, err := parseRenderOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	if opts.durations == "" && opts.fields == nil && opts.minLevel == 0 {
+		return item, nil
+	}
+
+	tree, err := toTree(item)
+	if err != nil {
+		return nil, err
+	}
+	if opts.minLevel > 0 {
+		if err := sliceLevels(tree, opts.minLevel, opts.maxLevel); err != nil {
+			return nil, err
+		}
+	}
+	if opts.fields != nil {
+		tree = project(tree, opts.fields)
+	}
+	if opts.durations != "" {
+		formatDurations(tree, opts.durations)
+	}
+	return tree, nil
+}
+
+// sliceLevels keeps only the entries of the item's level tables whose "level"
+// lies in [lo, hi]. It fails if the item has levels but none are in range.
+func sliceLevels(tree interface{}, lo, hi int) error {
+	obj, ok := tree.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	tables := []map[string]interface{}{obj}
+	if modes, ok := obj["modes"].([]interface{}); ok {
+		for _, m := range modes {
+			if mode, ok := m.(map[string]interface{}); ok {
+				tables = append(tables, mode)
+			}
+		}
+	}
+
+	total, kept := 0, 0
+	for _, t := range tables {
+		levels, ok := t["levels"].([]interface{})
+		if !ok {
+			continue
+		}
+		sliced := []interface{}{}
+		for _, l := range levels {
+			entry, _ := l.(map[string]interface{})
+			n, _ := entry["level"].(json.Number)
+			if v, err := n.Int64(); err == nil && int(v) >= lo && int(v) <= hi {
+				sliced = append(sliced, l)
+			}
+		}
+		total += len(levels)
+		kept += len(sliced)
+		t["levels"] = sliced
+	}
+
+	if total > 0 && kept == 0 {
+		return fmt.Errorf("no levels between %d and %d", lo, hi)
+	}
+	return nil
+}
+
+// project keeps only the given field paths in tree.
+func project(tree interface{}, paths [][]string) interface{} {
+	switch t := tree.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, v := range t {
+			out[i] = project(v, paths)
+			if entry, ok := out[i].(map[string]interface{}); ok {
+				src := v.(map[string]interface{})
+				for _, id := range []string{"level", "chargeLevel"} {
+					if val, ok := src[id]; ok {
+						entry[id] = val
+					}
+				}
+			}
+		}
+		return out
+	case map[string]interface{}:
+		children := make(map[string][][]string)
+		whole := make(map[string]bool)
+		for _, p := range paths {
+			if len(p) == 1 {
+				whole[p[0]] = true
+			} else {
+				children[p[0]] = append(children[p[0]], p[1:])
+			}
+		}
+
+		out := make(map[string]interface{})
+		for k, v := range t {
+			switch {
+			case whole[k]:
+				out[k] = v
+			case children[k] != nil:
+				out[k] = project(v, children[k])
+			}
+		}
+		return out
+	default:
+		return tree
+	}
+}
+
+// toTree converts a value into generic JSON maps and slices, keeping numbers
+// as json.Number so they are re-encoded exactly.
+func toTree(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// formatDurations rewrites every duration field in tree to the given format.
+func formatDurations(tree interface{}, format string) {
+	switch t := tree.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			if data.IsDurationField(k) {
+				if d, ok := durationValue(v); ok {
+					t[k] = formatDuration(d, format)
+					continue
+				}
+			}
+			formatDurations(v, format)
+		}
+	case []interface{}:
+		for _, v := range t {
+			formatDurations(v, format)
+		}
+	}
+}
+
+// durationValue extracts a Duration from either its encoded object form or
+// a raw duration string (as found in untyped documents).
+func durationValue(v interface{}) (data.Duration, bool) {
+	switch t := v.(type) {
+	case string:
+		d, err := data.ParseDuration(t)
+		return d, err == nil
+	case map[string]interface{}:
+		raw, _ := t["raw"].(string)
+		d, err := data.ParseDuration(raw)
+		return d, err == nil
+	}
+	return data.Duration{}, false
+}
+
+// formatDuration renders d in the requested format. Not-applicable durations
+// ("N/A") are rendered as null.
+func formatDuration(d data.Duration, format string) interface{} {
+	if !d.Valid {
+		return nil
+	}
+	switch format {
+	case durationsSeconds:
+		return d.Seconds
+	case durationsISO8601:
+		return d.ISO8601()
+	default:
+		return d.Human()
+	}
+}
@@ -3,9 +3,14 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"log/slog"
 	"net/http"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 // APIResponse is the standard envelope for all successful API responses.
@@ -17,7 +22,45 @@ type APIResponse struct {
 
 // Meta provides additional response metadata.
 type Meta struct {
+	// Cached reports whether the data was served from the server's cache
+	// rather than computed for this request.
 	Cached bool `json:"cached"`
+
+	Version   string `json:"version,omitempty"`   // dataset version, see data.Store.Version
+	RequestID string `json:"requestId,omitempty"` // also logged with the request
+	Count     *int   `json:"count,omitempty"`     // number of items in a list
+	Total     *int   `json:"total,omitempty"`     // number of items across every page
+	Next      string `json:"next,omitempty"`      // cursor of the next page, if any
+
+	// The attribution of the data file a single item was read from.
+	*data.Source
+}
+
+// newMeta returns the metadata common to every response. store may be nil
+// for responses not built from the data.
+func newMeta(r *http.Request, store *data.Store) *Meta {
+	m := &Meta{RequestID: middleware.GetReqID(r.Context())}
+	if store != nil {
+		m.Version = store.Version()
+	}
+	return m
+}
+
+// listMeta returns the metadata of a list of count items.
+func listMeta(r *http.Request, store *data.Store, count int) *Meta {
+	m := newMeta(r, store)
+	m.Count = &count
+	return m
+}
+
+// itemMeta returns the metadata of an item read from the data file at
+// subPath, including the file's source.
+func itemMeta(r *http.Request, store *data.Store, subPath string) *Meta {
+	m := newMeta(r, store)
+	if src, ok := store.Source(subPath); ok {
+		m.Source = &src
+	}
+	return m
 }
 
 // ErrorResponse is the standard envelope for error responses.
@@ -42,10 +85,20 @@ func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 }
 
 // Success sends a standardized successful response with optional metadata.
+// It sets a weak ETag hashed from data alone, since the metadata (e.g. the
+// request ID) differs between otherwise identical responses.
 func Success(w http.ResponseWriter, data interface{}, meta *Meta) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		InternalError(w, "failed to encode response: "+err.Error())
+		return
+	}
+	sum := sha256.Sum256(body)
+	w.Header().Set("ETag", `W/"`+hex.EncodeToString(sum[:16])+`"`)
+
 	writeJSON(w, http.StatusOK, APIResponse{
 		Status: "success",
-		Data:   data,
+		Data:   json.RawMessage(body),
 		Meta:   meta,
 	})
 }
@@ -58,6 +111,11 @@ func Error(w http.ResponseWriter, code int, message string) {
 	})
 }
 
+// BadRequest sends a 400 error response.
+func BadRequest(w http.ResponseWriter, message string) {
+	Error(w, http.StatusBadRequest, message)
+}
+
 // NotFound sends a 404 error response.
 func NotFound(w http.ResponseWriter, message string) {
 	Error(w, http.StatusNotFound, message)
@@ -20,6 +20,13 @@ func RootHandler() http.HandlerFunc {
 				"health":    "/health",
 				"buildings": "/api/buildings",
 				"troops":    "/api/troops",
+				"spells":    "/api/home_village/spells",
+				"heroes":    "/api/home_village/heroes",
+				"equipment": "/api/home_village/equipment",
+				"pets":      "/api/home_village/pets",
+				"siege":     "/api/home_village/siege_machines",
+				"districts": "/api/clan_capital/districts",
+				"search":    "/api/search?q=",
 			},
 		})
 	}
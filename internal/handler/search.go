@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/flapjacck/CoCDB/internal/search"
+)
+
+// defaultSearchLimit is the number of results returned when no limit is given.
+const defaultSearchLimit = 20
+
+// SearchHandler serves full-text search across every base and entity kind.
+type SearchHandler struct {
+	store *data.Store
+	index atomic.Pointer[search.Index]
+}
+
+// NewSearchHandler creates a handler backed by the given search index of
+// the data in store.
+func NewSearchHandler(store *data.Store, index *search.Index) *SearchHandler {
+	h := &SearchHandler{store: store}
+	h.index.Store(index)
+	return h
+}
+
+// SetIndex replaces the search index, e.g. after the data is reloaded.
+func (h *SearchHandler) SetIndex(index *search.Index) {
+	h.index.Store(index)
+}
+
+// Search handles GET /api/search?q=
+// Returns matching entities of every indexed kind (buildings, troops, spells,
+// heroes, equipment, pets, siege machines, districts and Town Halls) ranked
+// by relevance, each with the API path to fetch it. Results are paged like lists (see writeList), but
+// sorted by relevance or name and limited to defaultSearchLimit by default.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		BadRequest(w, "query parameter q is required")
+		return
+	}
+
+	opts, err := parseListOptions(r, []string{"relevance"}, "relevance")
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	if !r.URL.Query().Has("limit") {
+		opts.limit = defaultSearchLimit
+	}
+
+	results := h.index.Load().Search(q, 0)
+	entries := make([]listEntry[search.Result], len(results))
+	for i, res := range results {
+		// Negated, so ascending order puts the best matches first.
+		relevance := -int64(math.Round(res.Score * 100))
+		entries[i] = listEntry[search.Result]{cursor: cursor{Value: &relevance, Name: res.Name, Path: res.Path}, item: res}
+	}
+	writePage(w, r, h.store, opts, entries)
+}
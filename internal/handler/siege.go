@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5"
+)
+
+// SiegeMachinesHandler serves siege machine API endpoints.
+type SiegeMachinesHandler struct {
+	store *data.Store
+}
+
+// NewSiegeMachinesHandler creates a handler serving siege machines from the
+// given store.
+func NewSiegeMachinesHandler(store *data.Store) *SiegeMachinesHandler {
+	return &SiegeMachinesHandler{store: store}
+}
+
+// List handles GET /api/{base}/siege_machines
+// Returns every siege machine in the base.
+func (h *SiegeMachinesHandler) List(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+
+	items, err := h.store.ListItems(base + "/siege_machines")
+	if err != nil {
+		NotFound(w, "no siege machines in base: "+base)
+		return
+	}
+	setLastModified(w, h.store, base+"/siege_machines")
+	writeList(w, r, h.store, items)
+}
+
+// Get handles GET /api/{base}/siege_machines/{name}
+// Returns full data for a specific siege machine, including the Workshop
+// level that unlocks it.
+func (h *SiegeMachinesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	name := chi.URLParam(r, "name")
+
+	subPath := base + "/siege_machines/" + name
+	s, err := h.store.GetSiegeMachine(subPath)
+	if err != nil {
+		NotFound(w, "siege machine not found: "+name)
+		return
+	}
+	setLastModified(w, h.store, subPath)
+	writeItem(w, r, s, itemMeta(r, h.store, subPath))
+}
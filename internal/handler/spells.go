@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5"
+)
+
+// SpellsHandler serves spell-related API endpoints.
+type SpellsHandler struct {
+	store *data.Store
+}
+
+// NewSpellsHandler creates a handler serving spells from the given store.
+func NewSpellsHandler(store *data.Store) *SpellsHandler {
+	return &SpellsHandler{store: store}
+}
+
+// ListCategories handles GET /api/{base}/spells
+// Returns all spell categories (elixir, dark_elixir) with item counts.
+func (h *SpellsHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+
+	categories, err := h.store.ListCategories(base + "/spells")
+	if err != nil {
+		NotFound(w, "no spells in base: "+base)
+		return
+	}
+	setLastModified(w, h.store, base+"/spells")
+	writeCategories(w, r, h.store, categories)
+}
+
+// ListByCategory handles GET /api/{base}/spells/{category}
+// Returns all spells within a specific category.
+func (h *SpellsHandler) ListByCategory(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	category := chi.URLParam(r, "category")
+
+	items, err := h.store.ListItems(base + "/spells/" + category)
+	if err != nil {
+		NotFound(w, "spell category not found: "+category)
+		return
+	}
+	setLastModified(w, h.store, base+"/spells/"+category)
+	writeList(w, r, h.store, items)
+}
+
+// GetSpell handles GET /api/{base}/spells/{category}/{name}
+// Returns full data for a specific spell, including the spell factory
+// level that unlocks it.
+func (h *SpellsHandler) GetSpell(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	category := chi.URLParam(r, "category")
+	name := chi.URLParam(r, "name")
+
+	subPath := base + "/spells/" + category + "/" + name
+	item, err := h.store.GetSpell(subPath)
+	if err != nil {
+		NotFound(w, "spell not found: "+name)
+		return
+	}
+	setLastModified(w, h.store, subPath)
+
+	writeItem(w, r, item, itemMeta(r, h.store, subPath))
+}
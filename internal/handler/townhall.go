@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/flapjacck/CoCDB/internal/cache"
+	"github.com/flapjacck/CoCDB/internal/data"
+	"github.com/go-chi/chi/v5"
+)
+
+// TownHallHandler serves views of the game scoped to a Town Hall level.
+// The views are derived from every building in the base, so they are cached.
+type TownHallHandler struct {
+	store *data.Store
+	cache *cache.Cache[*data.TownHallView]
+}
+
+// NewTownHallHandler creates a handler with the given data store and cache.
+func NewTownHallHandler(store *data.Store, c *cache.Cache[*data.TownHallView]) *TownHallHandler {
+	return &TownHallHandler{store: store, cache: c}
+}
+
+// Get handles GET /api/{base}/townhall
+// Returns the Town Hall's own data: hitpoints, cost, storage and weapon per level.
+func (h *TownHallHandler) Get(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+
+	th, err := h.store.GetTownHall(base)
+	if err != nil {
+		NotFound(w, "no town hall data in base: "+base)
+		return
+	}
+	setLastModified(w, h.store, base+"/town_hall")
+	writeItem(w, r, th, itemMeta(r, h.store, base+"/town_hall/town_hall"))
+}
+
+// GetTownHall handles GET /api/{base}/townhall/{level}
+// Returns the Town Hall's own stats at the level, followed by every building
+// available there with its count and the max level it can reach. Bases whose
+// buildings are not available by Town Hall level respond 404.
+func (h *TownHallHandler) GetTownHall(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	level, err := strconv.Atoi(chi.URLParam(r, "level"))
+	if err != nil {
+		BadRequest(w, "town hall level must be an integer")
+		return
+	}
+
+	refs, err := h.store.ListBuildings(base)
+	if err != nil {
+		NotFound(w, "no buildings in base: "+base)
+		return
+	}
+	if data.MaxTownHall(refs) == 0 {
+		NotFound(w, "no town hall levels in base: "+base)
+		return
+	}
+	setLastModified(w, h.store, base)
+
+	cacheKey := "townhall:" + base + ":" + strconv.Itoa(level)
+	view, cached, err := h.cache.GetOrLoad(cacheKey, func() (*data.TownHallView, error) {
+		view, err := data.NewTownHallView(refs, level)
+		if err != nil {
+			return nil, err
+		}
+		if th, err := h.store.GetTownHall(base); err == nil {
+			view.Stats = th.Level(level)
+		}
+		return view, nil
+	})
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	meta := newMeta(r, h.store)
+	meta.Cached = cached
+	Success(w, view, meta)
+}
+
+// GetMaxOut handles GET /api/{base}/townhall/{level}/maxout
+// Returns the total cost and builder time to max every building at the Town
+// Hall level, with a breakdown per category. Like GetTownHall, it responds
+// 404 for bases without Town Hall levels.
+//
+// Query parameters:
+//   - from: a lower Town Hall level assumed already maxed (default 0, from scratch)
+//   - supercharges: "true" to include supercharges (default false)
+func (h *TownHallHandler) GetMaxOut(w http.ResponseWriter, r *http.Request) {
+	base := chi.URLParam(r, "base")
+	level, err := strconv.Atoi(chi.URLParam(r, "level"))
+	if err != nil {
+		BadRequest(w, "town hall level must be an integer")
+		return
+	}
+	from, err := queryInt(r, "from", 0)
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	supercharges := r.URL.Query().Get("supercharges") == "true"
+
+	refs, err := h.store.ListBuildings(base)
+	if err != nil {
+		NotFound(w, "no buildings in base: "+base)
+		return
+	}
+	if data.MaxTownHall(refs) == 0 {
+		NotFound(w, "no town hall levels in base: "+base)
+		return
+	}
+
+	report, err := data.NewMaxOutReport(refs, level, from, supercharges)
+	if err != nil {
+		BadRequest(w, err.Error())
+		return
+	}
+	setLastModified(w, h.store, base)
+	writeItem(w, r, report, newMeta(r, h.store))
+}
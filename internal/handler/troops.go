@@ -1,46 +1,34 @@
 package handler
 
 import (
-	"log/slog"
 	"net/http"
 
-	"github.com/flapjacck/CoCDB/internal/cache"
 	"github.com/flapjacck/CoCDB/internal/data"
 	"github.com/go-chi/chi/v5"
 )
 
 // TroopsHandler serves troop-related API endpoints.
 type TroopsHandler struct {
-	loader *data.Loader
-	cache  *cache.Cache
+	store *data.Store
 }
 
-// NewTroopsHandler creates a handler with the given data loader and cache.
-func NewTroopsHandler(loader *data.Loader, c *cache.Cache) *TroopsHandler {
-	return &TroopsHandler{loader: loader, cache: c}
+// NewTroopsHandler creates a handler serving troops from the given store.
+func NewTroopsHandler(store *data.Store) *TroopsHandler {
+	return &TroopsHandler{store: store}
 }
 
 // ListCategories handles GET /api/{base}/troops
 // Returns all troop categories (elixir, dark_elixir, super) with item counts.
 func (h *TroopsHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
 	base := chi.URLParam(r, "base")
-	cacheKey := "troops:categories:" + base
 
-	if cached, ok := h.cache.Get(cacheKey); ok {
-		Success(w, cached, nil)
-		return
-	}
-
-	troopsBase := base + "/troops"
-	categories, err := h.loader.ListCategories(troopsBase)
+	categories, err := h.store.ListCategories(base + "/troops")
 	if err != nil {
-		slog.Error("failed to list troop categories", "error", err, "base", base)
-		InternalError(w, "failed to load troop categories")
+		NotFound(w, "no troops in base: "+base)
 		return
 	}
-
-	h.cache.Set(cacheKey, categories)
-	Success(w, categories, nil)
+	setLastModified(w, h.store, base+"/troops")
+	writeCategories(w, r, h.store, categories)
 }
 
 // ListByCategory handles GET /api/{base}/troops/{category}
@@ -48,44 +36,31 @@ func (h *TroopsHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
 func (h *TroopsHandler) ListByCategory(w http.ResponseWriter, r *http.Request) {
 	base := chi.URLParam(r, "base")
 	category := chi.URLParam(r, "category")
-	cacheKey := "troops:list:" + base + ":" + category
 
-	if cached, ok := h.cache.Get(cacheKey); ok {
-		Success(w, cached, nil)
-		return
-	}
-
-	troopsBase := base + "/troops"
-	items, err := h.loader.ListItems(troopsBase + "/" + category)
+	items, err := h.store.ListItems(base + "/troops/" + category)
 	if err != nil {
 		NotFound(w, "troop category not found: "+category)
 		return
 	}
-
-	h.cache.Set(cacheKey, items)
-	Success(w, items, nil)
+	setLastModified(w, h.store, base+"/troops/"+category)
+	writeList(w, r, h.store, items)
 }
 
 // GetTroop handles GET /api/{base}/troops/{category}/{name}
-// Returns full data for a specific troop.
+// Returns full data for a specific troop, including the barracks level that
+// unlocks it.
 func (h *TroopsHandler) GetTroop(w http.ResponseWriter, r *http.Request) {
 	base := chi.URLParam(r, "base")
 	category := chi.URLParam(r, "category")
 	name := chi.URLParam(r, "name")
-	cacheKey := "troops:item:" + base + ":" + category + ":" + name
-
-	if cached, ok := h.cache.Get(cacheKey); ok {
-		Success(w, cached, nil)
-		return
-	}
 
-	troopsBase := base + "/troops"
-	item, err := h.loader.GetItem(troopsBase + "/" + category + "/" + name)
+	subPath := base + "/troops/" + category + "/" + name
+	item, err := h.store.GetTroop(subPath)
 	if err != nil {
 		NotFound(w, "troop not found: "+name)
 		return
 	}
+	setLastModified(w, h.store, subPath)
 
-	h.cache.Set(cacheKey, item)
-	Success(w, item, nil)
+	writeItem(w, r, item, itemMeta(r, h.store, subPath))
 }
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// bufferWriter holds back a response so it can be inspected before sending.
+// Headers are written straight to the underlying writer's header map.
+type bufferWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code without sending it.
+func (bw *bufferWriter) WriteHeader(code int) {
+	bw.status = code
+}
+
+// Write buffers the body.
+func (bw *bufferWriter) Write(b []byte) (int, error) {
+	return bw.body.Write(b)
+}
+
+// ConditionalGET is middleware that adds an ETag, a hash of the body, to
+// successful GET responses that do not already have one, and answers 304 Not
+// Modified when the request's If-None-Match lists it (compared weakly).
+// Without If-None-Match, If-Modified-Since is compared against the
+// Last-Modified header set by the handler, if any.
+func ConditionalGET(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		if bw.status != http.StatusOK {
+			w.WriteHeader(bw.status)
+			w.Write(bw.body.Bytes())
+			return
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(bw.body.Bytes())
+			etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", etag)
+		}
+
+		if notModified(r, etag, w.Header().Get("Last-Modified")) {
+			h := w.Header()
+			h.Del("Content-Type")
+			h.Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(bw.body.Bytes())
+	})
+}
+
+// notModified evaluates the request's conditional headers against the
+// response's validators, giving If-None-Match precedence (RFC 9110 13.2.2).
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		etag = strings.TrimPrefix(etag, "W/")
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || lastModified == "" {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	return err == nil && !modified.After(ims)
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConditionalGET(t *testing.T) {
+	modified := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"status":"success"}`
+
+	h := ConditionalGET(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		w.Write([]byte(body))
+	}))
+
+	// The first response carries the ETag the conditional requests send back.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || rec.Body.String() != body {
+		t.Fatalf("unconditional GET = %d, ETag %q, body %q", rec.Code, etag, rec.Body)
+	}
+
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		want    int
+	}{
+		{"no validators", "GET", "/", nil, http.StatusOK},
+		{"matching etag", "GET", "/", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag", "GET", "/", map[string]string{"If-None-Match": "W/" + etag}, http.StatusNotModified},
+		{"etag in list", "GET", "/", map[string]string{"If-None-Match": `"other", ` + etag}, http.StatusNotModified},
+		{"wildcard", "GET", "/", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"other etag", "GET", "/", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"unchanged since", "GET", "/", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"unchanged since later", "GET", "/", map[string]string{"If-Modified-Since": after}, http.StatusNotModified},
+		{"changed since", "GET", "/", map[string]string{"If-Modified-Since": before}, http.StatusOK},
+		{"invalid date", "GET", "/", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+		// If-None-Match takes precedence over If-Modified-Since either way.
+		{"etag mismatch over date", "GET", "/", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": after}, http.StatusOK},
+		{"etag match over date", "GET", "/", map[string]string{"If-None-Match": etag, "If-Modified-Since": before}, http.StatusNotModified},
+		{"error response", "GET", "/missing", map[string]string{"If-None-Match": "*"}, http.StatusNotFound},
+		{"not a GET", "POST", "/", map[string]string{"If-None-Match": "*"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Code != http.StatusNotModified {
+				return
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("304 has body %q", rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "" {
+				t.Errorf("304 has Content-Type %q", ct)
+			}
+			if got := rec.Header().Get("ETag"); got != etag {
+				t.Errorf("304 ETag = %q, want %q", got, etag)
+			}
+		})
+	}
+}
+
+func TestConditionalGETKeepsHandlerETag(t *testing.T) {
+	h := ConditionalGET(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `W/"v1"`)
+		w.Write([]byte("data"))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("ETag"); got != `W/"v1"` {
+		t.Errorf("ETag = %q, want the handler's W/\"v1\"", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match \"v1\" against W/\"v1\" = %d, want 304", rec.Code)
+	}
+}
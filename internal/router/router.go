@@ -2,6 +2,8 @@
 package router
 
 import (
+	"io/fs"
+	"log/slog"
 	"net/http"
 
 	"github.com/flapjacck/CoCDB/internal/cache"
@@ -9,13 +11,15 @@ import (
 	"github.com/flapjacck/CoCDB/internal/data"
 	"github.com/flapjacck/CoCDB/internal/handler"
 	mw "github.com/flapjacck/CoCDB/internal/middleware"
+	"github.com/flapjacck/CoCDB/internal/search"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 )
 
 // New creates and configures a chi router with all API routes and middleware.
-func New(cfg *config.Config) *chi.Mux {
+// Data is served from store and the favicon from staticFS.
+func New(cfg *config.Config, store *data.Store, staticFS fs.FS) *chi.Mux {
 	r := chi.NewRouter()
 
 	// --- Global Middleware Stack ---
@@ -34,14 +38,42 @@ func New(cfg *config.Config) *chi.Mux {
 	}))
 
 	// --- Dependencies ---
-	appCache := cache.New(cfg.CacheTTL)
-	loader := data.NewLoader(cfg.DataDir)
+	townHallCache := cache.New[*data.TownHallView](cfg.CacheTTL, cfg.CacheMaxEntries)
+	index, err := search.Build(store)
+	if err != nil {
+		slog.Error("failed to build search index", "error", err)
+		index = &search.Index{}
+	}
+	slog.Info("search index built", "documents", index.Size())
 
 	// --- Handlers ---
 	healthH := handler.NewHealthHandler()
-	buildingsH := handler.NewBuildingsHandler(loader, appCache)
-	troopsH := handler.NewTroopsHandler(loader, appCache)
-	faviconH := handler.NewFaviconHandler("static/favicon.ico")
+	buildingsH := handler.NewBuildingsHandler(store)
+	troopsH := handler.NewTroopsHandler(store)
+	spellsH := handler.NewSpellsHandler(store)
+	heroesH := handler.NewHeroesHandler(store)
+	siegeH := handler.NewSiegeMachinesHandler(store)
+	districtsH := handler.NewDistrictsHandler(store)
+	townHallH := handler.NewTownHallHandler(store, townHallCache)
+	relationsH := handler.NewRelationsHandler(store)
+	searchH := handler.NewSearchHandler(store, index)
+	faviconH := handler.NewFaviconHandler(staticFS, "favicon.ico")
+	adminH := handler.NewAdminHandler(map[string]handler.CacheStatser{
+		"townhall": townHallCache,
+	})
+
+	// Drop values derived from the previous data whenever it is reloaded.
+	store.OnReload(func(bases []string) {
+		for _, base := range bases {
+			townHallCache.DeletePrefix("townhall:" + base + ":")
+		}
+		ix, err := search.Build(store)
+		if err != nil {
+			slog.Error("failed to rebuild search index", "error", err)
+			return
+		}
+		searchH.SetIndex(ix)
+	})
 
 	// --- Custom Error Handlers ---
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
@@ -55,20 +87,64 @@ func New(cfg *config.Config) *chi.Mux {
 	r.Get("/", handler.RootHandler())
 	r.Method("GET", "/health", healthH)
 	r.Method("GET", "/favicon.ico", faviconH)
+	r.Get("/admin/cache", adminH.CacheStats)
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
+		r.Use(mw.ConditionalGET) // ETag and 304 Not Modified on every data endpoint
+
+		// Search across every base
+		r.Get("/search", searchH.Search)
+
 		// Base-specific routes
 		r.Route("/{base}", func(r chi.Router) {
+			r.Use(handler.KnownBase)
+
 			// Building endpoints
 			r.Get("/buildings", buildingsH.ListCategories)
 			r.Get("/buildings/{category}", buildingsH.ListByCategory)
 			r.Get("/buildings/{category}/{name}", buildingsH.GetBuilding)
+			r.Get("/buildings/{category}/{name}/upgrade", buildingsH.GetUpgrade)
+			r.Get("/buildings/{category}/{name}/relations", relationsH.Get)
 
 			// Troop endpoints
 			r.Get("/troops", troopsH.ListCategories)
 			r.Get("/troops/{category}", troopsH.ListByCategory)
 			r.Get("/troops/{category}/{name}", troopsH.GetTroop)
+			r.Get("/troops/{category}/{name}/relations", relationsH.Get)
+
+			// Spell endpoints
+			r.Get("/spells", spellsH.ListCategories)
+			r.Get("/spells/{category}", spellsH.ListByCategory)
+			r.Get("/spells/{category}/{name}", spellsH.GetSpell)
+			r.Get("/spells/{category}/{name}/relations", relationsH.Get)
+
+			// Hero, equipment and pet endpoints
+			r.Get("/heroes", heroesH.ListHeroes)
+			r.Get("/heroes/{name}", heroesH.GetHero)
+			r.Get("/heroes/{name}/relations", relationsH.Get)
+			r.Get("/equipment", heroesH.ListEquipment)
+			r.Get("/equipment/{name}", heroesH.GetEquipment)
+			r.Get("/equipment/{name}/relations", relationsH.Get)
+			r.Get("/pets", heroesH.ListPets)
+			r.Get("/pets/{name}", heroesH.GetPet)
+			r.Get("/pets/{name}/relations", relationsH.Get)
+
+			// Siege machine endpoints
+			r.Get("/siege_machines", siegeH.List)
+			r.Get("/siege_machines/{name}", siegeH.Get)
+			r.Get("/siege_machines/{name}/relations", relationsH.Get)
+
+			// Clan Capital district endpoints
+			r.Get("/districts", districtsH.List)
+			r.Get("/districts/{district}", districtsH.Get)
+			r.Get("/districts/{district}/buildings", districtsH.ListBuildings)
+			r.Get("/districts/{district}/relations", relationsH.Get)
+
+			// Town Hall endpoints
+			r.Get("/townhall", townHallH.Get)
+			r.Get("/townhall/{level}", townHallH.GetTownHall)
+			r.Get("/townhall/{level}/maxout", townHallH.GetMaxOut)
 		})
 	})
 
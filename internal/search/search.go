@@ -0,0 +1,330 @@
+// Package search provides an in-memory, typo-tolerant full-text index over
+// every data file served by the API.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+)
+
+// kinds lists the entity kinds indexed under each base.
+var kinds = []string{"buildings", "troops", "spells", "heroes", "equipment", "pets", "siege_machines", "districts", "town_hall"}
+
+// Field weights. A match in a more specific field ranks higher.
+const (
+	weightName        = 10.0
+	weightUnlock      = 6.0
+	weightDescription = 3.0
+	weightNotes       = 1.5
+)
+
+// Result is a single ranked search hit.
+type Result struct {
+	Name     string  `json:"name"`
+	Kind     string  `json:"kind"`
+	Base     string  `json:"base"`
+	Category string  `json:"category,omitempty"`
+	Path     string  `json:"path"`
+	Score    float64 `json:"score"`
+	Match    string  `json:"match"`
+	Text     string  `json:"text,omitempty"`
+}
+
+// field is one searchable piece of text in a document.
+type field struct {
+	key     string // JSON key the text came from (e.g. "unlockedUnit")
+	text    string
+	weight  float64
+	tokens  []string
+	compact string // lower-case letters and digits only
+}
+
+// document is an indexed data file.
+type document struct {
+	name, kind, base, category, path string
+	fields                           []field
+}
+
+// Index is an immutable search index. It is safe for concurrent use.
+type Index struct {
+	docs []document
+}
+
+// Build reads every data file of the indexed kinds from the store and
+// indexes its name, description, notes and unlock fields. Bases are indexed
+// in display order, so equally ranked results list the Home Village first.
+func Build(store *data.Store) (*Index, error) {
+	ix := &Index{}
+	for _, base := range data.Bases {
+		for _, kind := range kinds {
+			kindPath := base + "/" + kind
+			categories, err := store.ListCategories(kindPath)
+			if err != nil {
+				continue // not every base has every kind
+			}
+			// Flat kinds (e.g. heroes) keep their files in the kind
+			// directory itself rather than in category subdirectories.
+			dirs := append([]data.CategoryInfo{{Path: kindPath}}, categories...)
+			for _, c := range dirs {
+				items, err := store.ListItems(c.Path)
+				if err != nil {
+					return nil, err
+				}
+				for _, it := range items {
+					raw, err := store.GetItem(it.Path)
+					if err != nil {
+						return nil, err
+					}
+					doc, err := newDocument(raw)
+					if err != nil {
+						return nil, fmt.Errorf("failed to index %s: %w", it.Path, err)
+					}
+					doc.kind, doc.base, doc.category = kind, base, c.Name
+					doc.path = "/api/" + it.Path
+					if kind == "town_hall" {
+						// The Town Hall is served at /api/{base}/townhall,
+						// not under its data directory.
+						doc.kind, doc.path = "townhall", "/api/"+base+"/townhall"
+					}
+					if doc.name == "" {
+						doc.name = it.Name
+					}
+					doc.fields = append(doc.fields, newField("slug", it.Name, weightName))
+					ix.docs = append(ix.docs, doc)
+				}
+			}
+		}
+	}
+	return ix, nil
+}
+
+// Size returns the number of indexed documents.
+func (ix *Index) Size() int {
+	return len(ix.docs)
+}
+
+// newDocument extracts the searchable text from a raw data file.
+func newDocument(raw []byte) (document, error) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return document{}, err
+	}
+
+	var doc document
+	obj, _ := v.(map[string]interface{})
+	if name, ok := obj["name"].(string); ok {
+		doc.name = name
+		doc.fields = append(doc.fields, newField("name", name, weightName))
+	}
+	if desc, ok := obj["description"].(string); ok {
+		doc.fields = append(doc.fields, newField("description", desc, weightDescription))
+	}
+	collect(v, &doc)
+	return doc, nil
+}
+
+// collect walks a document adding notes and unlock fields at any depth.
+func collect(v interface{}, doc *document) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			switch {
+			case k == "notes":
+				if s, ok := val.(string); ok {
+					doc.fields = append(doc.fields, newField(k, s, weightNotes))
+				}
+			case strings.HasPrefix(k, "unlocked"):
+				for _, s := range stringValues(val) {
+					doc.fields = append(doc.fields, newField(k, s, weightUnlock))
+				}
+			default:
+				collect(val, doc)
+			}
+		}
+	case []interface{}:
+		for _, val := range t {
+			collect(val, doc)
+		}
+	}
+}
+
+// stringValues returns v as a list of strings, accepting a string or string array.
+func stringValues(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var out []string
+		for _, s := range t {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// newField tokenizes text for indexing.
+func newField(key, text string, weight float64) field {
+	return field{
+		key:     key,
+		text:    text,
+		weight:  weight,
+		tokens:  tokenize(text),
+		compact: compact(text),
+	}
+}
+
+// Search returns up to limit documents matching query, best first, or
+// every match if limit is 0.
+func (ix *Index) Search(query string, limit int) []Result {
+	qTokens := tokenize(query)
+	qCompact := compact(query)
+	if len(qTokens) == 0 {
+		return []Result{}
+	}
+
+	results := []Result{}
+	for _, doc := range ix.docs {
+		best, bestField := 0.0, field{}
+		for _, f := range doc.fields {
+			if s := f.score(qTokens, qCompact); s > best {
+				best, bestField = s, f
+			}
+		}
+		if best == 0 {
+			continue
+		}
+		r := Result{
+			Name:     doc.name,
+			Kind:     doc.kind,
+			Base:     doc.base,
+			Category: doc.category,
+			Path:     doc.path,
+			Score:    float64(int(best*100)) / 100,
+			Match:    bestField.key,
+		}
+		if bestField.key != "name" && bestField.key != "slug" {
+			r.Text = bestField.text
+		}
+		results = append(results, r)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Name < results[j].Name
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// score rates how well the query matches the field, or 0 for no match.
+//
+// The whole query compared with the whole field (ignoring spaces and
+// punctuation, so "xbow" matches "X-Bow") scores highest. Otherwise every
+// query token must match some field token exactly, by prefix, or within a
+// small edit distance; the field score is the average token score.
+func (f field) score(qTokens []string, qCompact string) float64 {
+	switch {
+	case f.compact == qCompact:
+		return f.weight * 1.0
+	case len(qCompact) >= 3 && strings.HasPrefix(f.compact, qCompact):
+		return f.weight * 0.9
+	}
+
+	total := 0.0
+	for _, q := range qTokens {
+		best := 0.0
+		for _, t := range f.tokens {
+			if s := tokenScore(q, t); s > best {
+				best = s
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return f.weight * 0.8 * total / float64(len(qTokens))
+}
+
+// tokenScore rates a single query token against a field token in [0, 1].
+func tokenScore(q, t string) float64 {
+	switch {
+	case q == t:
+		return 1
+	case len(q) >= 3 && strings.HasPrefix(t, q):
+		return 0.8
+	}
+
+	maxDist := maxEdits(q)
+	if maxDist == 0 {
+		return 0
+	}
+	// Compare against the field token and against its prefix of the same
+	// length, so "infernal" still matches "inferno".
+	d := levenshtein(q, t)
+	if len(t) > len(q) {
+		d = min(d, levenshtein(q, t[:len(q)]))
+	}
+	if d > maxDist {
+		return 0
+	}
+	return 0.6 - 0.15*float64(d-1)
+}
+
+// maxEdits returns the typo allowance for a query token of this length.
+func maxEdits(q string) int {
+	switch n := len(q); {
+	case n < 4:
+		return 0
+	case n < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// tokenize lower-cases text and splits it into letter/digit runs.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// compact lower-cases text and drops everything but letters and digits.
+func compact(text string) string {
+	return strings.Join(tokenize(text), "")
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,130 @@
+package search
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/flapjacck/CoCDB/internal/data"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"inferno", "inferno", 0},
+		{"infernal", "inferno", 2},
+		{"kitten", "sitting", 3},
+		{"mortr", "mortar", 1},
+		{"cannno", "cannon", 2},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	for q, want := range map[string]int{"bow": 0, "bomb": 1, "cannon": 1, "inferno": 2, "infernal": 2} {
+		if got := maxEdits(q); got != want {
+			t.Errorf("maxEdits(%q) = %d, want %d", q, got, want)
+		}
+	}
+}
+
+func TestTokenScore(t *testing.T) {
+	tests := []struct {
+		q, t string
+		want float64
+	}{
+		{"tower", "tower", 1},
+		{"inf", "inferno", 0.8},       // prefix
+		{"in", "inferno", 0},          // prefix too short
+		{"mortr", "mortar", 0.6},      // one edit
+		{"infernal", "inferno", 0.45}, // two edits
+		{"canon", "cannon", 0.6},
+		{"cannno", "cannon", 0},     // two edits, but only one allowed under 7 letters
+		{"infernl", "inferno", 0.6}, // up to two edits from 7 letters
+		{"infrnl", "inferno", 0},
+		{"bow", "bo", 0}, // no typos allowed under 4 letters
+		{"wizard", "archer", 0},
+	}
+	for _, tt := range tests {
+		if got := tokenScore(tt.q, tt.t); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("tokenScore(%q, %q) = %v, want %v", tt.q, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestFieldScore(t *testing.T) {
+	f := newField("name", "X-Bow", weightName)
+	if got := f.score(tokenize("xbow"), compact("xbow")); got != weightName {
+		t.Errorf("xbow scored %v against X-Bow, want %v", got, weightName)
+	}
+	if got := f.score(tokenize("x bow"), compact("x bow")); got != weightName {
+		t.Errorf("x bow scored %v against X-Bow, want %v", got, weightName)
+	}
+
+	f = newField("name", "Inferno Tower", weightName)
+	if got := f.score(tokenize("infer"), compact("infer")); got != weightName*0.9 {
+		t.Errorf("infer scored %v against Inferno Tower, want %v", got, weightName*0.9)
+	}
+	if got := f.score(tokenize("tower infernal"), compact("tower infernal")); got <= 0 {
+		t.Error("tower infernal did not match Inferno Tower")
+	}
+	if got := f.score(tokenize("tower wizard"), compact("tower wizard")); got != 0 {
+		t.Errorf("tower wizard scored %v against Inferno Tower, want 0 (every token must match)", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	store, violations, err := data.Load(os.DirFS("../../data"))
+	if err != nil || len(violations) > 0 {
+		t.Fatalf("Load = %v, %v", violations, err)
+	}
+	ix, err := Build(store)
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+
+	tests := []struct {
+		query, top string
+	}{
+		{"xbow", "/api/home_village/buildings/defensive/x_bow"},
+		{"X-Bow", "/api/home_village/buildings/defensive/x_bow"},
+		{"infernal", "/api/home_village/buildings/defensive/inferno_tower"},
+		{"inferno tower", "/api/home_village/buildings/defensive/inferno_tower"},
+		{"air sweeper", "/api/home_village/buildings/defensive/air_sweeper"},
+		{"town hall", "/api/home_village/townhall"},
+	}
+	for _, tt := range tests {
+		results := ix.Search(tt.query, 3)
+		if len(results) == 0 || results[0].Path != tt.top {
+			t.Errorf("Search(%q) = %+v, want %s first", tt.query, results, tt.top)
+		}
+	}
+
+	// Unlock fields are indexed, so a unit's name finds the building that
+	// unlocks it.
+	found := false
+	for _, r := range ix.Search("Wall Wrecker", 0) {
+		if r.Path == "/api/home_village/buildings/army/workshop" && r.Match == "unlockedSiegeMachine" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Search(Wall Wrecker) did not find the Workshop by its unlock field")
+	}
+
+	if got := ix.Search("qqqqzz", 0); len(got) != 0 {
+		t.Errorf("Search(qqqqzz) = %+v, want no results", got)
+	}
+	if got := ix.Search("tower", 2); len(got) != 2 {
+		t.Errorf("Search(tower, 2) returned %d results, want 2", len(got))
+	}
+}
@@ -0,0 +1,99 @@
+// Package watch detects changes to files in a directory tree by polling
+// their sizes and modification times.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"sort"
+	"time"
+)
+
+// fileState is what a poll records about a file.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Poller reports the files of a file system that were added, modified or
+// removed since the previous poll. It is not safe for concurrent use.
+type Poller struct {
+	fsys  fs.FS
+	files map[string]fileState
+}
+
+// NewPoller creates a Poller over fsys, recording the current state of every
+// file so the first Poll only reports later changes.
+func NewPoller(fsys fs.FS) (*Poller, error) {
+	p := &Poller{fsys: fsys}
+	files, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+	p.files = files
+	return p, nil
+}
+
+// Poll returns the sorted paths of the files changed since the last poll.
+func (p *Poller) Poll() ([]string, error) {
+	files, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for path, st := range files {
+		if old, ok := p.files[path]; !ok || old != st {
+			changed = append(changed, path)
+		}
+	}
+	for path := range p.files {
+		if _, ok := files[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	p.files = files
+	return changed, nil
+}
+
+// scan records the size and modification time of every file.
+func (p *Poller) scan() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	err := fs.WalkDir(p.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return files, err
+}
+
+// Run polls every interval until ctx is done, calling onChange with the
+// changed paths whenever there are any, and onError when a poll fails.
+func Run(ctx context.Context, p *Poller, interval time.Duration, onChange func(changed []string), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := p.Poll()
+			if err != nil {
+				onError(err)
+				continue
+			}
+			if len(changed) > 0 {
+				onChange(changed)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
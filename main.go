@@ -4,31 +4,57 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 	"time"
 
 	"github.com/flapjacck/CoCDB/internal/config"
+	"github.com/flapjacck/CoCDB/internal/data"
 	"github.com/flapjacck/CoCDB/internal/router"
+	"github.com/flapjacck/CoCDB/internal/watch"
 )
 
 func main() {
 	// Load configuration from environment variables.
 	cfg := config.Load()
 
+	// Subcommands (e.g. "cocdb validate") run instead of the server.
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(cfg, os.Args[1:]))
+	}
+
 	// Set up structured logging based on environment.
 	initLogger(cfg)
 
+	// Load every data file into memory, failing fast if any does not match
+	// its category template.
+	fsys, source := dataFS(cfg)
+	store, err := loadData(fsys, source)
+	if err != nil {
+		slog.Error("data validation failed", "error", err)
+		os.Exit(1)
+	}
+
 	slog.Info("starting CoCDB API server",
 		"port", cfg.Port,
 		"environment", cfg.Environment,
+		"data", source,
 	)
 
 	// Build the HTTP router with all routes and middleware.
-	r := router.New(cfg)
+	r := router.New(cfg, store, staticFS())
+
+	// Reload the data whenever files under DATA_DIR change.
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	if cfg.DataDir != "" && cfg.ReloadInterval > 0 {
+		go watchData(watchCtx, store, fsys, cfg.ReloadInterval)
+	}
 
 	// Configure the HTTP server with timeouts for production resilience.
 	srv := &http.Server{
@@ -55,6 +81,7 @@ func main() {
 	sig := <-quit
 
 	slog.Info("received shutdown signal", "signal", sig.String())
+	stopWatch()
 
 	// Give active connections up to 30 seconds to finish.
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -68,6 +95,67 @@ func main() {
 	slog.Info("server stopped gracefully")
 }
 
+// loadData validates the data in fsys and loads it into a store, logging
+// each violation found, or the load time and entity counts on success.
+// source describes where the data comes from.
+func loadData(fsys fs.FS, source string) (*data.Store, error) {
+	start := time.Now()
+	store, violations, err := data.Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range violations {
+		slog.Error("invalid data", "file", v.File, "path", v.Path, "message", v.Message)
+	}
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("%d violation(s) in %s", len(violations), source)
+	}
+
+	attrs := []any{"source", source, "version", store.Version(), "duration", time.Since(start).Round(time.Millisecond)}
+	counts := store.Counts()
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		attrs = append(attrs, kind, counts[kind])
+	}
+	slog.Info("data loaded", attrs...)
+	return store, nil
+}
+
+// watchData polls fsys every interval and reloads the store when files
+// change. If the changed data is invalid, the violations are logged and the
+// previous data keeps being served.
+func watchData(ctx context.Context, store *data.Store, fsys fs.FS, interval time.Duration) {
+	p, err := watch.NewPoller(fsys)
+	if err != nil {
+		slog.Error("failed to watch data", "error", err)
+		return
+	}
+	slog.Info("watching data for changes", "interval", interval)
+
+	watch.Run(ctx, p, interval, func(changed []string) {
+		start := time.Now()
+		violations, err := store.Reload(fsys, changed)
+		if err != nil {
+			slog.Error("data reload failed, serving previous data", "error", err, "changed", changed)
+			return
+		}
+		for _, v := range violations {
+			slog.Error("invalid data", "file", v.File, "path", v.Path, "message", v.Message)
+		}
+		if len(violations) > 0 {
+			slog.Error("data reload rejected, serving previous data", "violations", len(violations), "changed", changed)
+			return
+		}
+		slog.Info("data reloaded", "changed", changed, "version", store.Version(), "duration", time.Since(start).Round(time.Millisecond))
+	}, func(err error) {
+		slog.Error("failed to poll data", "error", err)
+	})
+}
+
 // initLogger configures the global slog logger.
 // Production uses JSON output for structured log aggregation.
 // Development uses human-readable text format.